@@ -3,19 +3,28 @@ package alog
 import (
 	"context"
 	"log/slog"
-	"runtime"
+	"slices"
 	"time"
 )
 
 type handlerKey struct{}
 
+// Handler returns the context handler with the attributes and groups added by
+// With, WithAttrs and WithGroup, or the fallback handler if ctx has none,
+// see SetFallback.
 func Handler(ctx context.Context) slog.Handler {
-	handler, ok := ctx.Value(handlerKey{}).(slog.Handler)
+	node, ok := ctx.Value(handlerKey{}).(*attrsNode)
 	if !ok {
-		return slog.Default().Handler()
+		fallbackHit()
+
+		return fallbackHandler()
+	}
+
+	if node.fallback {
+		fallbackHit()
 	}
 
-	return handler
+	return node.handler()
 }
 
 func Context(ctx context.Context, h slog.Handler) context.Context {
@@ -23,33 +32,45 @@ func Context(ctx context.Context, h slog.Handler) context.Context {
 		return ctx
 	}
 
-	return context.WithValue(ctx, handlerKey{}, h)
+	return context.WithValue(ctx, handlerKey{}, newRootNode(ctx, h))
 }
 
+// With adds attributes to the context for the default and named channels,
+// they are applied to the handlers only when a record is logged with the context.
 func With(ctx context.Context, args ...any) context.Context {
-	h := Handler(ctx)
-
-	h = h.WithAttrs(
-		argsToAttrSlice(args),
-	)
-
-	return Context(ctx, h)
+	return withAttrs(ctx, argsToAttrSlice(args))
 }
 
 func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
-	h := Handler(ctx)
+	return withAttrs(ctx, slices.Clone(attrs))
+}
+
+func withAttrs(ctx context.Context, attrs []slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+
+	node := attrsNodeFromContext(ctx).withAttrs(attrs)
 
-	h = h.WithAttrs(attrs)
+	ctx = context.WithValue(ctx, handlerKey{}, node)
 
-	return Context(ctx, h)
+	return withChannels(ctx, func(node *attrsNode) *attrsNode {
+		return node.withAttrs(attrs)
+	})
 }
 
 func WithGroup(ctx context.Context, groupName string) context.Context {
-	h := Handler(ctx)
+	if groupName == "" {
+		return ctx
+	}
+
+	node := attrsNodeFromContext(ctx).withGroup(groupName)
 
-	h = h.WithGroup(groupName)
+	ctx = context.WithValue(ctx, handlerKey{}, node)
 
-	return Context(ctx, h)
+	return withChannels(ctx, func(node *attrsNode) *attrsNode {
+		return node.withGroup(groupName)
+	})
 }
 
 func Info(ctx context.Context, msg string, args ...any) {
@@ -60,6 +81,9 @@ func Warn(ctx context.Context, msg string, args ...any) {
 	alog(ctx, slog.LevelWarn, msg, args...)
 }
 
+// Error logs a record at Error, errors in args are not classified, so a
+// context cancellation is logged at Error too. Use Err to log an error
+// with the level and outcome of the context Classifier.
 func Error(ctx context.Context, msg string, args ...any) {
 	alog(ctx, slog.LevelError, msg, args...)
 }
@@ -72,37 +96,72 @@ func Log(ctx context.Context, level slog.Level, msg string, args ...any) {
 	alog(ctx, level, msg, args...)
 }
 
+// LogAttrs is the typed variant of Log. The values of attributes built by
+// constructors such as slog.Int are not boxed, while values in the args of
+// Log and the level functions are boxed by the caller before alog is called,
+// as for slog.Logger, alog cannot avoid these allocations.
 func LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
 	alogAttrs(ctx, level, msg, attrs...)
 }
 
+// LogDepth logs a record with the source of the caller depth frames above
+// the caller of LogDepth, depth 0 is the caller of LogDepth.
+func LogDepth(ctx context.Context, depth int, level slog.Level, msg string, args ...any) {
+	alogDepth(ctx, depth+1, level, msg, args)
+}
+
+func LogAttrsDepth(ctx context.Context, depth int, level slog.Level, msg string, attrs ...slog.Attr) {
+	alogAttrsDepth(ctx, depth+1, level, msg, attrs)
+}
+
 func alog(ctx context.Context, level slog.Level, msg string, args ...any) {
-	h := Handler(ctx)
-	if !h.Enabled(ctx, level) {
+	alogDepth(ctx, 2, level, msg, args)
+}
+
+// alogDepth logs a record with the source of the caller skip frames above
+// the caller of alogDepth.
+func alogDepth(ctx context.Context, skip int, level slog.Level, msg string, args []any) {
+	level, args, skipRecord := applyLoggedPolicy(ctx, level, args)
+	if skipRecord {
 		return
 	}
 
-	pcs := [1]uintptr{}
-	runtime.Callers(3, pcs[:])
+	h, enabled := enabledHandler(ctx, level)
+	if !enabled {
+		return
+	}
 
-	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
-	attrs := argsToAttrSlice(args)
+	r := slog.NewRecord(time.Now(), level, msg, callerPC(skip+1))
 
-	r.AddAttrs(attrs...)
+	addArgs(&r, args)
 
 	_ = h.Handle(ctx, r)
 }
 
 func alogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
-	h := Handler(ctx)
-	if !h.Enabled(ctx, level) {
+	alogAttrsDepth(ctx, 2, level, msg, attrs)
+}
+
+func alogAttrsDepth(ctx context.Context, skip int, level slog.Level, msg string, attrs []slog.Attr) {
+	h, enabled := enabledHandler(ctx, level)
+	if !enabled {
 		return
 	}
 
-	pcs := [1]uintptr{}
-	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, callerPC(skip+1))
+
+	r.AddAttrs(attrs...)
+
+	_ = h.Handle(ctx, r)
+}
 
-	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+func alogPC(ctx context.Context, pc uintptr, level slog.Level, msg string, attrs ...slog.Attr) {
+	h, enabled := enabledHandler(ctx, level)
+	if !enabled {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), level, msg, pc)
 
 	r.AddAttrs(attrs...)
 
@@ -112,10 +171,11 @@ func alogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.
 const badKey = "!BADKEY"
 
 func argsToAttrSlice(args []any) []slog.Attr {
-	var (
-		attr  slog.Attr
-		attrs []slog.Attr
-	)
+	return appendArgs(nil, args)
+}
+
+func appendArgs(attrs []slog.Attr, args []any) []slog.Attr {
+	var attr slog.Attr
 
 	for len(args) > 0 {
 		attr, args = argsToAttr(args)
@@ -125,6 +185,30 @@ func argsToAttrSlice(args []any) []slog.Attr {
 	return attrs
 }
 
+// addArgsBatch is the number of attributes converted on the stack before
+// they are added to a record, the record grows its storage once per batch.
+const addArgsBatch = 16
+
+// addArgs adds args to r without an intermediate heap slice.
+func addArgs(r *slog.Record, args []any) {
+	var (
+		batch [addArgsBatch]slog.Attr
+		n     int
+	)
+
+	for len(args) > 0 {
+		batch[n], args = argsToAttr(args)
+		n++
+
+		if n == len(batch) {
+			r.AddAttrs(batch[:n]...)
+			n = 0
+		}
+	}
+
+	r.AddAttrs(batch[:n]...)
+}
+
 func argsToAttr(args []any) (slog.Attr, []any) {
 	switch x := args[0].(type) {
 	case string:
@@ -132,19 +216,28 @@ func argsToAttr(args []any) (slog.Attr, []any) {
 			return slog.String(badKey, x), nil
 		}
 
-		return slog.Any(x, args[1]), args[2:]
+		return slog.Any(x, argValue(args[1])), args[2:]
 
 	case slog.Attr:
 		return x, args[1:]
 
 	case error:
-		return errorAttr(x), args[1:]
+		return ErrorAttr(x), args[1:]
 
 	default:
 		return slog.Any(badKey, x), args[1:]
 	}
 }
 
+// argValue makes func() any values lazy, see Lazy.
+func argValue(v any) any {
+	if f, ok := v.(func() any); ok {
+		return LazyValue[any](f)
+	}
+
+	return v
+}
+
 const ErrorKey = "err"
 
 var nilError = slog.Attr{
@@ -152,50 +245,10 @@ var nilError = slog.Attr{
 	Value: slog.StringValue("nil"),
 }
 
-func errorAttr(err error) slog.Attr {
+func ErrorAttr(err error) slog.Attr {
 	if err == nil {
 		return nilError
 	}
 
 	return slog.String(ErrorKey, err.Error())
 }
-
-const OpKey = "op"
-
-type Operation struct {
-	ctx context.Context
-}
-
-func Start(ctx context.Context, opName string, additionalArgs ...any) Operation {
-	const minArgsAmount = 1
-
-	args := make([]any, 0, len(additionalArgs)+minArgsAmount)
-
-	args = append(args, slog.String(OpKey, opName))
-	args = append(args, additionalArgs...)
-
-	ctx = With(ctx, args...)
-
-	op := Operation{
-		ctx: ctx,
-	}
-
-	alog(op.ctx, slog.LevelInfo, "start")
-
-	return op
-}
-
-func (op Operation) Finish() {
-	alogAttrs(op.ctx, slog.LevelInfo, "finish")
-}
-
-func (op Operation) Error(err error, additionalArgs ...any) {
-	const minArgsAmount = 1
-
-	args := make([]any, 0, len(additionalArgs)+minArgsAmount)
-
-	args = append(args, errorAttr(err))
-	args = append(args, additionalArgs...)
-
-	alog(op.ctx, slog.LevelError, "error", args...)
-}
@@ -0,0 +1,130 @@
+package alog_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func Test_Start(t *testing.T) {
+	const opName = "Test_Start"
+
+	op := alogtest.NewOp(opName,
+		"key", "value",
+		"age", 10,
+	)
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Error(io.ErrUnexpectedEOF, "reason", "db"),
+		op.Finish(),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	aop := alog.Start(ctx,
+		opName,
+		"key", "value",
+		"age", 10,
+	)
+	defer aop.Finish()
+
+	aop.Error(io.ErrUnexpectedEOF, "reason", "db")
+}
+
+func Test_Start_Scope(t *testing.T) {
+	op := alogtest.Scope{}.
+		With("request_id", "1").
+		WithGroup("user").
+		With("id", 10).
+		Op("update", "field", "name")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Error(nil),
+		op.Finish(),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.With(ctx, "request_id", "1")
+	ctx = alog.WithGroup(ctx, "user")
+	ctx = alog.With(ctx, "id", 10)
+
+	aop := alog.Start(ctx, "update", "field", "name")
+	aop.Error(nil)
+	aop.Finish()
+}
+
+func Test_WithGroup(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("initial"),
+		alogtest.Info("get update", slog.Group("user", "age", 10, slog.Group("money", "value", 0))),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	alog.Info(ctx, "initial")
+
+	ctx = alog.WithGroup(ctx, "user")
+	ctx = alog.WithAttrs(ctx, slog.Int("age", 10))
+	ctx = alog.WithGroup(ctx, "money")
+	ctx = alog.WithAttrs(ctx, slog.Int("value", 0))
+
+	alog.Info(ctx, "get update")
+}
+
+func Test_Operation_Log(t *testing.T) {
+	op := alogtest.NewOp("load", "id", 1)
+
+	handler := alogtest.NewHandler(t,
+		&alogtest.AssertOptions{
+			CheckOrder: true,
+			Level:      slog.LevelInfo,
+		},
+		op.Start(),
+		op.Debug("debug", "step", 1),
+		op.Info("info", "step", 2),
+		op.Warn("warn", "step", 3),
+		op.Log(slog.LevelError, "log", "step", 4),
+		op.Log(slog.LevelInfo, "log attrs", "step", 5),
+		op.Info("callee", "depth", 1),
+		op.Finish(),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	aop := alog.Start(ctx, "load", "id", 1)
+	defer aop.Finish()
+
+	aop.Debug("debug", "step", 1)
+	aop.Info("info", "step", 2)
+	aop.Warn("warn", "step", 3)
+	aop.Log(slog.LevelError, "log", "step", 4)
+	aop.LogAttrs(slog.LevelInfo, "log attrs", slog.Int("step", 5))
+
+	callee(aop.Context())
+}
+
+func callee(ctx context.Context) {
+	alog.Info(ctx, "callee", "depth", 1)
+}
+
+func Test_Operation_CallerSource(t *testing.T) {
+	alogtest.AssertCallerSource(t, func(ctx context.Context) {
+		op := alog.Start(ctx, "op")
+		op.Debug("debug")
+		op.Info("info")
+		op.Warn("warn")
+		op.Log(slog.LevelError, "log")
+		op.LogAttrs(slog.LevelError, "log attrs")
+		op.Error(io.ErrUnexpectedEOF)
+		op.Finish()
+	})
+}
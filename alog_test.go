@@ -11,8 +11,6 @@ import (
 	"reflect"
 	"testing"
 	"time"
-
-	"github.com/amidgo/alog/alogtest"
 )
 
 func Test_Context_Handler(t *testing.T) {
@@ -135,7 +133,7 @@ func Test_argsToAttrSlice(t *testing.T) {
 	}
 }
 
-func Test_errorAttr(t *testing.T) {
+func Test_ErrorAttr(t *testing.T) {
 	type errorAttrTest struct {
 		Name         string
 		Error        error
@@ -163,7 +161,7 @@ func Test_errorAttr(t *testing.T) {
 	for _, tst := range tests {
 		t.Run(tst.Name,
 			func(t *testing.T) {
-				attr := errorAttr(tst.Error)
+				attr := ErrorAttr(tst.Error)
 
 				if !reflect.DeepEqual(attr, tst.ExpectedAttr) {
 					t.Fatalf("attrs not equal, expected %+v, actual %+v", tst.ExpectedAttr, attr)
@@ -188,11 +186,11 @@ func Test_Error(t *testing.T) {
 
 	slogArgs := []any{
 		"key", "value",
-		errorAttr(http.ErrServerClosed),
+		ErrorAttr(http.ErrServerClosed),
 		"int", 100,
 		"err", bufio.ErrTooLong,
 		slog.Any("ctx", ctx),
-		errorAttr(io.ErrUnexpectedEOF),
+		ErrorAttr(io.ErrUnexpectedEOF),
 	}
 	alogArgs := []any{
 		"key", "value",
@@ -224,7 +222,7 @@ func Test_Log_Methods(t *testing.T) {
 		"key", "value",
 		"int", 100,
 		slog.Any("ctx", ctx),
-		errorAttr(io.ErrUnexpectedEOF),
+		ErrorAttr(io.ErrUnexpectedEOF),
 	}
 
 	fullLogScenario(ctx, slogLogger, args, args)
@@ -288,57 +286,12 @@ func replaceTimeKey(staticTime time.Time) func(_ []string, a slog.Attr) slog.Att
 	}
 }
 
-func Test_Start(t *testing.T) {
-	const opName = "Test_Start"
-
-	handler := alogtest.NewHandler(t,
-		(*alogtest.AssertOptions)(nil),
-		alogtest.Info("start",
-			OpKey, opName,
-			"key", "value",
-			"age", 10,
-		),
-		alogtest.Error("error",
-			OpKey, opName,
-			"key", "value",
-			"age", 10,
-			ErrorKey, io.ErrUnexpectedEOF.Error(),
-			"reason", "db",
-		),
-		alogtest.Info("finish",
-			OpKey, opName,
-			"key", "value",
-			"age", 10,
-		),
-	)
-
-	ctx := Context(t.Context(), handler)
-
-	op := Start(ctx,
-		opName,
-		"key", "value",
-		"age", 10,
-	)
-	defer op.Finish()
-
-	op.Error(io.ErrUnexpectedEOF, "reason", "db")
-}
+func Test_WithGroup_EmptyName(t *testing.T) {
+	ctx := Context(t.Context(), slog.NewTextHandler(io.Discard, nil))
 
-func Test_WithGroup(t *testing.T) {
-	handler := alogtest.NewHandler(t,
-		(*alogtest.AssertOptions)(nil),
-		alogtest.Info("initial"),
-		alogtest.Info("get update", slog.Group("user", "age", 10, slog.Group("money", "value", 0))),
-	)
+	groupCtx := WithGroup(ctx, "")
 
-	ctx := Context(t.Context(), handler)
-
-	Info(ctx, "initial")
-
-	ctx = WithGroup(ctx, "user")
-	ctx = WithAttrs(ctx, slog.Int("age", 10))
-	ctx = WithGroup(ctx, "money")
-	ctx = WithAttrs(ctx, slog.Int("value", 0))
-
-	Info(ctx, "get update")
+	if groupCtx != ctx {
+		t.Fatal("context has been modified")
+	}
 }
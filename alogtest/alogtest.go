@@ -12,6 +12,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/amidgo/alog"
 )
 
 func Time() slog.Attr {
@@ -23,9 +25,16 @@ func Time() slog.Attr {
 }
 
 type Operation struct {
-	Level slog.Level
-	Msg   string
-	Args  []any
+	Level  slog.Level
+	Msg    string
+	Args   []any
+	Source Source
+}
+
+func (op Operation) At(source Source) Operation {
+	op.Source = source
+
+	return op
 }
 
 func Debug(msg string, args ...any) Operation {
@@ -65,9 +74,24 @@ type Tester interface {
 	Cleanup(func())
 }
 
+type helperTester interface {
+	Helper()
+}
+
+type errorfTester interface {
+	Errorf(string, ...any)
+}
+
 type AssertOptions struct {
 	CheckOrder bool
 	AddSource  bool
+	// ShowSource adds the source location of actual records to the
+	// mismatch table reported through Errorf.
+	ShowSource bool
+	// Level is the minimum enabled level, all levels are enabled if nil.
+	Level slog.Leveler
+	// OperationLevels overrides Level for records of alog operations by name.
+	OperationLevels map[string]slog.Leveler
 }
 
 func assertOptionsCheckOrder(opts *AssertOptions) bool {
@@ -86,11 +110,23 @@ func assertOptionsAddSource(opts *AssertOptions) bool {
 	return false
 }
 
+func assertOptionsShowSource(opts *AssertOptions) bool {
+	if opts != nil {
+		return opts.ShowSource
+	}
+
+	return false
+}
+
 func NewHandler(
 	tester Tester,
 	opts *AssertOptions,
 	ops ...Operation,
 ) slog.Handler {
+	if h, ok := tester.(helperTester); ok {
+		h.Helper()
+	}
+
 	newHandler := newTextHandler
 
 	h := recordsCollector{
@@ -98,6 +134,7 @@ func NewHandler(
 		newHandler: newHandler,
 		mutates:    []func(h slog.Handler) slog.Handler{},
 		logs:       &logs{},
+		levels:     assertOptionsLevels(opts),
 	}
 
 	assert := assertOperationsExecuted(tester, h.logs, opts, ops)
@@ -109,17 +146,35 @@ func NewHandler(
 
 func assertOperationsExecuted(tester Tester, logs *logs, opts *AssertOptions, ops []Operation) func() {
 	return func() {
+		if h, ok := tester.(helperTester); ok {
+			h.Helper()
+		}
+
+		levels := assertOptionsLevels(opts)
+
+		ops, disabledOps := splitDisabledOperations(levels, ops)
+
+		if !assertDisabledRecords(tester, disabledOps, logs.Disabled()) {
+			return
+		}
+
 		actualRecords := logs.Records()
 
 		expectedRecords := makeExpectedRecords(newTextHandler, ops)
 
 		if !assertOptionsCheckOrder(opts) {
-			slices.Sort(actualRecords)
-			slices.Sort(expectedRecords)
+			slices.SortStableFunc(actualRecords, compareLogRecords)
+			slices.SortStableFunc(expectedRecords, compareExpectedRecords)
+		}
+
+		if errorf, ok := tester.(errorfTester); ok {
+			errorfMismatches(errorf, expectedRecords, actualRecords, assertOptionsShowSource(opts))
+
+			return
 		}
 
 		if len(expectedRecords) != len(actualRecords) {
-			fatalfInvalidRecords(tester, expectedRecords, actualRecords)
+			fatalfInvalidRecords(tester, expectedRecordsText(expectedRecords), logRecordsText(actualRecords))
 
 			return
 		}
@@ -128,8 +183,14 @@ func assertOperationsExecuted(tester Tester, logs *logs, opts *AssertOptions, op
 			expectedRecord := expectedRecords[i]
 			actualRecord := actualRecords[i]
 
-			if expectedRecord != actualRecord {
-				fatalfInvalidRecordByIndex(tester, i, expectedRecord, actualRecord)
+			if expectedRecord.text != actualRecord.text {
+				fatalfInvalidRecordByIndex(tester, i, expectedRecord.text, actualRecord.text)
+
+				return
+			}
+
+			if !expectedRecord.source.match(actualRecord.pc) {
+				fatalfInvalidSourceByIndex(tester, i, expectedRecord.source, actualRecord.pc)
 
 				return
 			}
@@ -137,9 +198,28 @@ func assertOperationsExecuted(tester Tester, logs *logs, opts *AssertOptions, op
 	}
 }
 
-func makeExpectedRecords(newHandler func(io.Writer) slog.Handler, ops []Operation) []string {
+type expectedRecord struct {
+	text   string
+	source Source
+}
+
+func compareExpectedRecords(a, b expectedRecord) int {
+	return strings.Compare(a.text, b.text)
+}
+
+func expectedRecordsText(records []expectedRecord) []string {
+	texts := make([]string, 0, len(records))
+
+	for _, rec := range records {
+		texts = append(texts, rec.text)
+	}
+
+	return texts
+}
+
+func makeExpectedRecords(newHandler func(io.Writer) slog.Handler, ops []Operation) []expectedRecord {
 	ctx := context.Background()
-	expectedRecords := make([]string, 0)
+	expectedRecords := make([]expectedRecord, 0)
 
 	buf := new(bytes.Buffer)
 
@@ -151,7 +231,12 @@ func makeExpectedRecords(newHandler func(io.Writer) slog.Handler, ops []Operatio
 
 		log.Log(ctx, op.Level, op.Msg, op.Args...)
 
-		expectedRecords = append(expectedRecords, buf.String())
+		expectedRecords = append(expectedRecords,
+			expectedRecord{
+				text:   buf.String(),
+				source: op.Source,
+			},
+		)
 	}
 
 	return expectedRecords
@@ -215,9 +300,15 @@ type recordsCollector struct {
 	newHandler func(io.Writer) slog.Handler
 	mutates    []func(h slog.Handler) slog.Handler
 	logs       *logs
+	levels     levels
+	op         string
 }
 
-var _ slog.Handler = (*recordsCollector)(nil)
+var (
+	_ slog.Handler          = (*recordsCollector)(nil)
+	_ alog.DisabledRecorder = (*recordsCollector)(nil)
+	_ alog.AttrsEnabler     = (*recordsCollector)(nil)
+)
 
 func (h recordsCollector) clone() recordsCollector {
 	h.mutates = slices.Clip(h.mutates)
@@ -225,15 +316,36 @@ func (h recordsCollector) clone() recordsCollector {
 	return h
 }
 
-func (h recordsCollector) Enabled(context.Context, slog.Level) bool {
+func (h recordsCollector) Enabled(_ context.Context, level slog.Level) bool {
+	return h.levels.enabled(h.op, level)
+}
+
+// EnabledByAttrs reports true, the operation levels and the disabled records
+// depend on the op attribute added to the handler.
+func (h recordsCollector) EnabledByAttrs() bool {
 	return true
 }
 
+// RecordDisabled records a log call dropped by alog because its level is
+// disabled, unlike Enabled it is not called by guards that log nothing.
+func (h recordsCollector) RecordDisabled(_ context.Context, level slog.Level) {
+	h.logs.pushDisabled(
+		disabledRecord{
+			level: level,
+			op:    h.op,
+		},
+	)
+}
+
 func (h recordsCollector) WithAttrs(attrs []slog.Attr) slog.Handler {
 	h = h.clone()
 
 	h.mutates = append(h.mutates, withAttrs(attrs))
 
+	if op, ok := attrsOperationName(attrs); ok {
+		h.op = op
+	}
+
 	return h
 }
 
@@ -281,7 +393,12 @@ func (h recordsCollector) Handle(ctx context.Context, record slog.Record) error
 		return fmt.Errorf("handler.Handle: %w", err)
 	}
 
-	h.logs.push(buf.String())
+	h.logs.push(
+		logRecord{
+			text: buf.String(),
+			pc:   record.PC,
+		},
+	)
 
 	return nil
 }
@@ -297,28 +414,61 @@ func source(r slog.Record) *slog.Source {
 	}
 }
 
-type logs struct {
-	mu      sync.Mutex
-	records []string
+type logRecord struct {
+	text string
+	pc   uintptr
+}
+
+func compareLogRecords(a, b logRecord) int {
+	return strings.Compare(a.text, b.text)
 }
 
-func (l *logs) Records() []string {
-	records := make([]string, len(l.records))
+func logRecordsText(records []logRecord) []string {
+	texts := make([]string, 0, len(records))
 
+	for _, rec := range records {
+		texts = append(texts, rec.text)
+	}
+
+	return texts
+}
+
+type logs struct {
+	mu       sync.Mutex
+	records  []logRecord
+	disabled []disabledRecord
+}
+
+func (l *logs) Records() []logRecord {
 	l.mu.Lock()
-	copy(records, l.records)
+	records := slices.Clone(l.records)
 	l.mu.Unlock()
 
 	return records
 }
 
-func (l *logs) push(record string) {
+func (l *logs) push(record logRecord) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	l.records = append(l.records, record)
 }
 
+func (l *logs) Disabled() []disabledRecord {
+	l.mu.Lock()
+	disabled := slices.Clone(l.disabled)
+	l.mu.Unlock()
+
+	return disabled
+}
+
+func (l *logs) pushDisabled(record disabledRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.disabled = append(l.disabled, record)
+}
+
 const minLevel slog.Level = math.MinInt
 
 func newTextHandler(w io.Writer) slog.Handler {
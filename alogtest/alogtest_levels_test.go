@@ -0,0 +1,117 @@
+package alogtest
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/amidgo/alog"
+)
+
+func Test_Handler_Level(t *testing.T) {
+	handler := NewHandler(t,
+		&AssertOptions{
+			CheckOrder: true,
+			Level:      slog.LevelInfo,
+		},
+		Debug("debug"),
+		Info("info"),
+		Warn("warn"),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	alog.Debug(ctx, "debug")
+	alog.Info(ctx, "info")
+	alog.Warn(ctx, "warn")
+}
+
+func Test_Handler_OperationLevels(t *testing.T) {
+	handler := NewHandler(t,
+		&AssertOptions{
+			CheckOrder: true,
+			Level:      slog.LevelInfo,
+			OperationLevels: map[string]slog.Leveler{
+				"verbose": slog.LevelDebug,
+				"quiet":   slog.LevelError,
+			},
+		},
+		Debug("debug"),
+		Debug("debug", alog.OpKey, "verbose"),
+		Info("info", alog.OpKey, "quiet"),
+		Error("error", alog.OpKey, "quiet"),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	alog.Debug(ctx, "debug")
+	alog.Debug(alog.With(ctx, alog.OpKey, "verbose"), "debug")
+
+	ctx = alog.With(ctx, alog.OpKey, "quiet")
+
+	alog.Info(ctx, "info")
+	alog.Error(ctx, "error")
+}
+
+func Test_Handler_DisabledNotExpected(t *testing.T) {
+	const expectedMessage = `
+INVALID DISABLED RECORDS
+EXPECTED:
+[
+----
+]
+ACTUAL:
+[
+----
+    level=DEBUG
+----
+    level=DEBUG op=op
+----
+]
+`
+
+	tester := newMockTester(t, expectedMessage)
+
+	handler := NewHandler(tester,
+		&AssertOptions{
+			Level: slog.LevelInfo,
+		},
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	alog.Debug(ctx, "debug")
+	alog.Debug(alog.With(ctx, alog.OpKey, "op"), "debug")
+}
+
+func Test_Handler_DisabledNotRequested(t *testing.T) {
+	tester := newFatalfStubTester(t)
+
+	handler := NewHandler(tester,
+		&AssertOptions{
+			Level: slog.LevelWarn,
+		},
+		Info("info"),
+		Warn("warn"),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	alog.Warn(ctx, "warn")
+}
+
+func Test_Handler_EnabledGuard(t *testing.T) {
+	handler := NewHandler(t,
+		&AssertOptions{
+			Level: slog.LevelInfo,
+		},
+		Info("info"),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	if alog.Handler(ctx).Enabled(ctx, slog.LevelDebug) {
+		t.Fatal("debug level enabled")
+	}
+
+	alog.Info(ctx, "info")
+}
@@ -0,0 +1,129 @@
+package alogtest
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amidgo/alog"
+)
+
+type errorfMockTester struct {
+	tb       testing.TB
+	helpers  int
+	messages []string
+}
+
+func (m *errorfMockTester) Fatalf(format string, args ...any) {
+	m.tb.Fatalf("unexpected Fatalf call: "+format, args...)
+}
+
+func (m *errorfMockTester) Errorf(format string, args ...any) {
+	m.messages = append(m.messages, fmt.Sprintf(format, args...))
+}
+
+func (m *errorfMockTester) Helper() {
+	m.helpers++
+}
+
+func (m *errorfMockTester) Cleanup(cleanup func()) {
+	m.tb.Cleanup(cleanup)
+}
+
+func newErrorfMockTester(tb testing.TB, expectedMessages ...string) *errorfMockTester {
+	tester := &errorfMockTester{
+		tb: tb,
+	}
+
+	tb.Cleanup(func() {
+		if !slices.Equal(expectedMessages, tester.messages) {
+			tb.Fatalf(
+				"messages not equal\n\nexpected:\n[%s]\n\nactual:\n[%s]",
+				strings.Join(expectedMessages, "\n"),
+				strings.Join(tester.messages, "\n"),
+			)
+		}
+
+		if tester.helpers == 0 {
+			tb.Fatal("Helper not called")
+		}
+	})
+
+	return tester
+}
+
+func Test_Handler_Errorf_AllMismatches(t *testing.T) {
+	const expectedMessage = `
+INVALID RECORDS: 3 MISMATCHES, 3 EXPECTED, 4 ACTUAL
+INDEX  EXPECTED                                            ACTUAL
+0      time=2023-08-08T20:14:06.000Z level=INFO msg=first  time=2023-08-08T20:14:06.000Z level=WARN msg=first
+2      time=2023-08-08T20:14:06.000Z level=INFO msg=third  time=2023-08-08T20:14:06.000Z level=INFO msg=third key=value
+3      <missing>                                           time=2023-08-08T20:14:06.000Z level=INFO msg=fourth
+`
+
+	tester := newErrorfMockTester(t, expectedMessage)
+
+	h := NewHandler(tester,
+		(*AssertOptions)(nil),
+		Info("first"),
+		Info("second"),
+		Info("third"),
+	)
+
+	log := slog.New(h)
+
+	log.Warn("first")
+	log.Info("second")
+	log.Info("third", "key", "value")
+	log.Info("fourth")
+}
+
+func Test_Handler_Errorf_Success(t *testing.T) {
+	tester := newErrorfMockTester(t)
+
+	h := NewHandler(tester,
+		(*AssertOptions)(nil),
+		Info("first"),
+	)
+
+	slog.New(h).Info("first")
+}
+
+func Test_Handler_Errorf_ShowSource(t *testing.T) {
+	tester := &errorfMockTester{tb: t}
+
+	t.Cleanup(func() {
+		if len(tester.messages) != 1 {
+			t.Fatalf("expected one message, actual %d", len(tester.messages))
+		}
+
+		msg := tester.messages[0]
+
+		for _, expected := range []string{
+			"INVALID RECORDS: 2 MISMATCHES, 2 EXPECTED, 2 ACTUAL",
+			"SOURCE",
+			`marker="not a log line"`,
+			"alogtest_report_test.go:",
+		} {
+			if !strings.Contains(msg, expected) {
+				t.Fatalf("message does not contain %q\n%s", expected, msg)
+			}
+		}
+	})
+
+	h := NewHandler(tester,
+		&AssertOptions{
+			CheckOrder: true,
+			ShowSource: true,
+		},
+		Info("first"),
+		Info("second").At(Source{Marker: "not a log line"}),
+	)
+
+	ctx := alog.Context(t.Context(), h)
+
+	alog.Warn(ctx, "first")
+	alog.Info(ctx, "second")
+}
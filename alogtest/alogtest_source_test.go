@@ -1,9 +1,10 @@
 package alogtest
 
 import (
+	"context"
 	"io"
 	"log/slog"
-	"path/filepath"
+	"os"
 	"runtime"
 	"strings"
 	"testing"
@@ -12,36 +13,19 @@ import (
 )
 
 func Test_Source(t *testing.T) {
+	op := NewOp("op")
+
 	handler := NewHandler(t,
 		&AssertOptions{
 			CheckOrder: true,
-			AddSource:  true,
 		},
-		Debug("debug",
-			testSource(49),
-		),
-		Info("info",
-			testSource(50),
-		),
-		Warn("warn",
-			testSource(51),
-		),
-		Error("error",
-			testSource(52),
-		),
-		Info("start",
-			testSource(53),
-			slog.String(alog.OpKey, "op"),
-		),
-		Error("error",
-			testSource(54),
-			slog.String(alog.OpKey, "op"),
-			slog.String(alog.ErrorKey, io.ErrUnexpectedEOF.Error()),
-		),
-		Info("finish",
-			testSource(55),
-			slog.String(alog.OpKey, "op"),
-		),
+		Debug("debug").At(Source{Function: "Test_Source", Marker: `alog.Debug(ctx, "debug")`}),
+		Info("info").At(Source{File: "alogtest_source_test.go", Marker: `alog.Info(ctx, "info")`}),
+		Warn("warn").At(Source{Marker: `alog.Warn(ctx, "warn")`}),
+		Error("error").At(Source{Marker: `alog.Error(ctx, "error")`}),
+		op.Start().At(Source{Marker: `alog.Start(ctx, "op")`}),
+		op.Error(io.ErrUnexpectedEOF).At(Source{Marker: "aop.Error(io.ErrUnexpectedEOF)"}),
+		op.Finish().At(Source{Function: "alogtest.Test_Source", Marker: "aop.Finish()"}),
 	)
 
 	ctx := alog.Context(t.Context(), handler)
@@ -50,47 +34,133 @@ func Test_Source(t *testing.T) {
 	alog.Info(ctx, "info")
 	alog.Warn(ctx, "warn")
 	alog.Error(ctx, "error")
-	op := alog.Start(ctx, "op")
-	op.Error(io.ErrUnexpectedEOF)
-	op.Finish()
+	aop := alog.Start(ctx, "op")
+	aop.Error(io.ErrUnexpectedEOF)
+	aop.Finish()
 }
 
-func testSource(line int) slog.Attr {
-	return slog.Any(
-		slog.SourceKey,
-		&slog.Source{
-			Function: "Test_Source",
-			File:     getCurrentFilePath(),
-			Line:     line,
+func Test_Source_Invalid(t *testing.T) {
+	tester := newFatalfStubTester(t)
+
+	handler := NewHandler(tester,
+		(*AssertOptions)(nil),
+		Info("info").At(Source{Marker: "another line"}),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	alog.Info(ctx, "info")
+}
+
+func Test_AddSource(t *testing.T) {
+	pc, file, _, _ := runtime.Caller(0)
+
+	handler := NewHandler(t,
+		&AssertOptions{
+			CheckOrder: true,
+			AddSource:  true,
 		},
+		Info("add source",
+			slog.Any(
+				slog.SourceKey,
+				&slog.Source{
+					Function: runtime.FuncForPC(pc).Name(),
+					File:     file,
+					Line:     statementLine(t, file, `alog.Info(ctx, "add source")`),
+				},
+			),
+		),
 	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	alog.Info(ctx, "add source")
+}
+
+// statementLine returns the number of the only line of file that consists
+// of the statement.
+func statementLine(t *testing.T, file, statement string) int {
+	t.Helper()
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("read %s: %s", file, err)
+	}
+
+	line := 0
+
+	for i, text := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(text) != statement {
+			continue
+		}
+
+		if line != 0 {
+			t.Fatalf("statement %q is not unique in %s", statement, file)
+		}
+
+		line = i + 1
+	}
+
+	if line == 0 {
+		t.Fatalf("statement %q not found in %s", statement, file)
+	}
+
+	return line
+}
+
+func Test_AssertCallerSource(t *testing.T) {
+	AssertCallerSource(t, func(ctx context.Context) {
+		alog.Debug(ctx, "debug")
+		alog.Info(ctx, "info")
+		alog.Warn(ctx, "warn")
+		alog.Error(ctx, "error")
+		alog.Log(ctx, slog.LevelInfo, "log")
+		alog.LogAttrs(ctx, slog.LevelInfo, "log attrs")
+
+		op := alog.Start(ctx, "op")
+		op.Error(io.ErrUnexpectedEOF)
+		op.Finish()
+	})
+}
+
+func Test_AssertCallerSource_InternalFrame(t *testing.T) {
+	tester := newFatalfStubTester(t)
+
+	AssertCallerSource(tester, func(ctx context.Context) {
+		logHelper(ctx)
+	})
 }
 
-func getCurrentFilePath() string {
-	pcs := make([]uintptr, 1)
+func Test_AssertCallerSource_AllInvalid(t *testing.T) {
+	tester := &errorfMockTester{tb: t}
 
-	// Пропускаем:
-	//  0 - текущий вызов runtime.Callers
-	//  1 - сама функция getCurrentFilePath
-	skip := 2
+	AssertCallerSource(tester, func(ctx context.Context) {
+		logHelper(ctx)
+		alog.Info(ctx, "direct")
+		logHelper(ctx)
+	})
 
-	// Получаем Program Counters (PC) для текущего кадра
-	n := runtime.Callers(skip, pcs)
-	if n == 0 {
-		return "unknown"
+	if tester.helpers == 0 {
+		t.Fatal("Helper not called")
 	}
 
-	// Преобразуем PC в Frames
-	frames := runtime.CallersFrames(pcs[:n])
-	frame, _ := frames.Next()
+	if len(tester.messages) != 2 {
+		t.Fatalf("expected 2 reported records, actual %d:\n%s", len(tester.messages), strings.Join(tester.messages, "\n"))
+	}
 
-	// Очищаем путь от лишних частей (опционально)
-	cleanPath := frame.File
-	if idx := strings.LastIndex(cleanPath, "/vendor/"); idx != -1 {
-		cleanPath = cleanPath[idx+len("/vendor/"):]
+	for i, index := range []string{"BY 0 INDEX", "BY 2 INDEX"} {
+		if !strings.Contains(tester.messages[i], index) {
+			t.Fatalf("message %d does not report %s:\n%s", i, index, tester.messages[i])
+		}
 	}
+}
+
+func logHelper(ctx context.Context) {
+	alog.Info(ctx, "helper")
+}
+
+func Test_AssertCallerSource_NoRecords(t *testing.T) {
+	tester := newFatalfStubTester(t)
 
-	// Получаем абсолютный путь
-	absPath, _ := filepath.Abs(cleanPath)
-	return absPath
+	AssertCallerSource(tester, func(context.Context) {})
 }
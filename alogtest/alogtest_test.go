@@ -359,16 +359,6 @@ func parallelCase(tester Tester, opts *AssertOptions, clientsCount int) {
 	wg.Wait()
 }
 
-func multipleOperations(ops []Operation, count int) []Operation {
-	result := make([]Operation, 0, count*len(ops))
-
-	for range count {
-		result = append(result, ops...)
-	}
-
-	return result
-}
-
 type errorHandler struct{}
 
 func (errorHandler) WithGroup(string) slog.Handler {
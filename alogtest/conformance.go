@@ -0,0 +1,259 @@
+package alogtest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amidgo/alog"
+)
+
+type handlerTest struct {
+	name string
+	ops  []Operation
+	opts *AssertOptions
+	run  func(ctx context.Context)
+}
+
+func RunHandlerTests(t *testing.T, newHandler func(next slog.Handler) slog.Handler) {
+	for _, tst := range handlerTests() {
+		t.Run(tst.name, func(t *testing.T) {
+			logs := &logs{}
+
+			h := newHandler(newTextHandler(logsWriter{logs: logs}))
+
+			t.Cleanup(assertOperationsExecuted(t, logs, tst.opts, tst.ops))
+
+			tst.run(alog.Context(t.Context(), h))
+		})
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		testHandlerEnabled(t, newHandler)
+	})
+
+	t.Run("record not modified", func(t *testing.T) {
+		testHandlerRecordNotModified(t, newHandler)
+	})
+}
+
+type logsWriter struct {
+	logs *logs
+}
+
+func (w logsWriter) Write(p []byte) (int, error) {
+	w.logs.push(logRecord{text: string(p)})
+
+	return len(p), nil
+}
+
+func handlerTests() []handlerTest {
+	const concurrency = 100
+
+	return []handlerTest{
+		{
+			name: "levels",
+			ops: []Operation{
+				Debug("debug"),
+				Info("info"),
+				Warn("warn"),
+				Error("error"),
+				{Level: slog.LevelWarn + 2, Msg: "custom"},
+			},
+			run: func(ctx context.Context) {
+				alog.Debug(ctx, "debug")
+				alog.Info(ctx, "info")
+				alog.Warn(ctx, "warn")
+				alog.Error(ctx, "error")
+				alog.Log(ctx, slog.LevelWarn+2, "custom")
+			},
+		},
+		{
+			name: "attrs order",
+			ops: []Operation{
+				Info("msg", "a", 1, "b", 2, "c", 3, "d", 4),
+			},
+			run: func(ctx context.Context) {
+				ctx = alog.With(ctx, "a", 1)
+				ctx = alog.WithAttrs(ctx, slog.Int("b", 2))
+
+				alog.Info(ctx, "msg", "c", 3, slog.Int("d", 4))
+			},
+		},
+		{
+			name: "group nesting",
+			ops: []Operation{
+				Info("msg",
+					"a", 1,
+					slog.Group("g1",
+						"b", 2,
+						slog.Group("g2", "c", 3),
+					),
+				),
+			},
+			run: func(ctx context.Context) {
+				ctx = alog.With(ctx, "a", 1)
+				ctx = alog.WithGroup(ctx, "g1")
+				ctx = alog.With(ctx, "b", 2)
+				ctx = alog.WithGroup(ctx, "g2")
+
+				alog.Info(ctx, "msg", "c", 3)
+			},
+		},
+		{
+			name: "empty group name",
+			ops: []Operation{
+				Info("msg", "a", 1),
+			},
+			run: func(ctx context.Context) {
+				ctx = alog.WithGroup(ctx, "")
+
+				alog.Info(ctx, "msg", "a", 1)
+			},
+		},
+		{
+			name: "empty group omitted",
+			ops: []Operation{
+				Info("msg", "a", 1),
+				Info("msg"),
+			},
+			run: func(ctx context.Context) {
+				alog.Info(ctx, "msg", "a", 1, slog.Group("empty"))
+
+				ctx = alog.WithGroup(ctx, "empty")
+
+				alog.Info(ctx, "msg")
+			},
+		},
+		{
+			name: "bad key",
+			ops: []Operation{
+				Info("msg", "key", "value", "dangling"),
+				Info("msg", "!BADKEY", 100),
+			},
+			run: func(ctx context.Context) {
+				alog.Info(ctx, "msg", "key", "value", "dangling")
+				alog.Info(ctx, "msg", 100)
+			},
+		},
+		{
+			name: "error args",
+			ops: []Operation{
+				Error("msg", "key", "value", alog.ErrorKey, io.ErrUnexpectedEOF.Error()),
+			},
+			run: func(ctx context.Context) {
+				alog.Error(ctx, "msg", "key", "value", io.ErrUnexpectedEOF)
+			},
+		},
+		{
+			name: "operation",
+			ops: []Operation{
+				NewOp("op", "key", "value").Start(),
+				NewOp("op", "key", "value").Error(io.ErrUnexpectedEOF),
+				NewOp("op", "key", "value").Finish(),
+			},
+			run: func(ctx context.Context) {
+				op := alog.Start(ctx, "op", "key", "value")
+				op.Error(io.ErrUnexpectedEOF)
+				op.Finish()
+			},
+		},
+		{
+			name: "concurrency",
+			ops:  multipleOperations([]Operation{Info("msg", "a", 1, slog.Group("g", "b", 2))}, concurrency),
+			opts: &AssertOptions{CheckOrder: false},
+			run: func(ctx context.Context) {
+				wg := sync.WaitGroup{}
+
+				wg.Add(concurrency)
+
+				for range concurrency {
+					go func() {
+						defer wg.Done()
+
+						ctx := alog.With(ctx, "a", 1)
+						ctx = alog.WithGroup(ctx, "g")
+
+						alog.Info(ctx, "msg", "b", 2)
+					}()
+				}
+
+				wg.Wait()
+			},
+		},
+	}
+}
+
+func multipleOperations(ops []Operation, count int) []Operation {
+	result := make([]Operation, 0, count*len(ops))
+
+	for range count {
+		result = append(result, ops...)
+	}
+
+	return result
+}
+
+func testHandlerEnabled(t *testing.T, newHandler func(next slog.Handler) slog.Handler) {
+	next := slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo})
+
+	handlers := map[string]slog.Handler{
+		"handler":    newHandler(next),
+		"with attrs": newHandler(next).WithAttrs([]slog.Attr{slog.Int("a", 1)}),
+		"with group": newHandler(next).WithGroup("g"),
+	}
+
+	for name, h := range handlers {
+		if h.Enabled(t.Context(), slog.LevelDebug) {
+			t.Errorf("%s: enabled level DEBUG disabled by next handler", name)
+		}
+	}
+}
+
+func testHandlerRecordNotModified(t *testing.T, newHandler func(next slog.Handler) slog.Handler) {
+	h := newHandler(newTextHandler(io.Discard))
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+	record.AddAttrs(
+		slog.Int("a", 1),
+		slog.String("b", "value"),
+		slog.Bool("c", true),
+		slog.Group("d", "e", 2),
+		slog.Float64("f", 0.5),
+		slog.Duration("g", time.Second),
+	)
+
+	expectedAttrs := recordAttrs(record)
+
+	err := h.Handle(t.Context(), record)
+	if err != nil {
+		t.Fatalf("handle record: %s", err)
+	}
+
+	actualAttrs := recordAttrs(record)
+
+	if len(expectedAttrs) != len(actualAttrs) {
+		t.Fatalf("record attrs modified\nexpected:\n%v\nactual:\n%v", expectedAttrs, actualAttrs)
+	}
+
+	for i := range expectedAttrs {
+		if !expectedAttrs[i].Equal(actualAttrs[i]) {
+			t.Fatalf("record attrs modified\nexpected:\n%v\nactual:\n%v", expectedAttrs, actualAttrs)
+		}
+	}
+}
+
+func recordAttrs(record slog.Record) []slog.Attr {
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+
+		return true
+	})
+
+	return attrs
+}
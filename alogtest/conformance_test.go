@@ -0,0 +1,39 @@
+package alogtest
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func Test_RunHandlerTests_Identity(t *testing.T) {
+	RunHandlerTests(t, func(next slog.Handler) slog.Handler {
+		return next
+	})
+}
+
+type wrapHandler struct {
+	next slog.Handler
+}
+
+func (h wrapHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h wrapHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return wrapHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h wrapHandler) WithGroup(name string) slog.Handler {
+	return wrapHandler{next: h.next.WithGroup(name)}
+}
+
+func (h wrapHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.next.Handle(ctx, record.Clone())
+}
+
+func Test_RunHandlerTests_Wrapper(t *testing.T) {
+	RunHandlerTests(t, func(next slog.Handler) slog.Handler {
+		return wrapHandler{next: next}
+	})
+}
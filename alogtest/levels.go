@@ -0,0 +1,138 @@
+package alogtest
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/amidgo/alog"
+)
+
+type levels struct {
+	level slog.Leveler
+	ops   map[string]slog.Leveler
+}
+
+func assertOptionsLevels(opts *AssertOptions) levels {
+	if opts != nil {
+		return levels{
+			level: opts.Level,
+			ops:   opts.OperationLevels,
+		}
+	}
+
+	return levels{}
+}
+
+func (l levels) enabled(op string, level slog.Level) bool {
+	return level >= l.minLevel(op)
+}
+
+func (l levels) minLevel(op string) slog.Level {
+	if leveler, ok := l.ops[op]; ok && op != "" {
+		return leveler.Level()
+	}
+
+	if l.level != nil {
+		return l.level.Level()
+	}
+
+	return minLevel
+}
+
+func attrsOperationName(attrs []slog.Attr) (string, bool) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == alog.OpKey {
+			return attrs[i].Value.String(), true
+		}
+	}
+
+	return "", false
+}
+
+func operationName(op Operation) string {
+	record := slog.NewRecord(time.Time{}, op.Level, op.Msg, 0)
+	record.Add(op.Args...)
+
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+
+		return true
+	})
+
+	name, _ := attrsOperationName(attrs)
+
+	return name
+}
+
+type disabledRecord struct {
+	level slog.Level
+	op    string
+}
+
+func (r disabledRecord) String() string {
+	if r.op == "" {
+		return fmt.Sprintf("level=%s", r.level)
+	}
+
+	return fmt.Sprintf("level=%s %s=%s", r.level, alog.OpKey, r.op)
+}
+
+func compareDisabledRecords(a, b disabledRecord) int {
+	return strings.Compare(a.String(), b.String())
+}
+
+func splitDisabledOperations(levels levels, ops []Operation) (enabled []Operation, disabled []disabledRecord) {
+	for _, op := range ops {
+		name := operationName(op)
+
+		if levels.enabled(name, op.Level) {
+			enabled = append(enabled, op)
+
+			continue
+		}
+
+		disabled = append(disabled,
+			disabledRecord{
+				level: op.Level,
+				op:    name,
+			},
+		)
+	}
+
+	return enabled, disabled
+}
+
+func assertDisabledRecords(tester Tester, expected, actual []disabledRecord) bool {
+	if h, ok := tester.(helperTester); ok {
+		h.Helper()
+	}
+
+	slices.SortFunc(expected, compareDisabledRecords)
+	slices.SortFunc(actual, compareDisabledRecords)
+
+	if slices.Equal(expected, actual) {
+		return true
+	}
+
+	tester.Fatalf("\nINVALID DISABLED RECORDS\nEXPECTED:\n%s\nACTUAL:\n%s\n",
+		disabledRecordsForTesterMessage(expected),
+		disabledRecordsForTesterMessage(actual),
+	)
+
+	return false
+}
+
+func disabledRecordsForTesterMessage(records []disabledRecord) string {
+	texts := make([]string, 0, len(records))
+
+	for _, rec := range records {
+		texts = append(texts, rec.String()+"\n")
+	}
+
+	return recordsForTesterMessage(texts)
+}
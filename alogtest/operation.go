@@ -0,0 +1,144 @@
+package alogtest
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/amidgo/alog"
+)
+
+type scopeLayer struct {
+	group string
+	args  []any
+}
+
+type Scope struct {
+	layers []scopeLayer
+}
+
+func (s Scope) With(args ...any) Scope {
+	s.layers = append(s.layers[:len(s.layers):len(s.layers)], scopeLayer{args: args})
+
+	return s
+}
+
+func (s Scope) WithGroup(groupName string) Scope {
+	if groupName == "" {
+		return s
+	}
+
+	s.layers = append(s.layers[:len(s.layers):len(s.layers)], scopeLayer{group: groupName})
+
+	return s
+}
+
+func (s Scope) Log(level slog.Level, msg string, args ...any) Operation {
+	return Operation{
+		Level: level,
+		Msg:   msg,
+		Args:  s.args(args),
+	}
+}
+
+func (s Scope) args(args []any) []any {
+	for i := len(s.layers) - 1; i >= 0; i-- {
+		layer := s.layers[i]
+
+		if layer.group != "" {
+			args = []any{slog.Group(layer.group, args...)}
+
+			continue
+		}
+
+		args = append(layer.args[:len(layer.args):len(layer.args)], args...)
+	}
+
+	return args
+}
+
+func (s Scope) Op(name string, args ...any) Op {
+	const minArgsAmount = 1
+
+	opArgs := make([]any, 0, len(args)+minArgsAmount)
+
+	opArgs = append(opArgs, slog.String(alog.OpKey, name))
+	opArgs = append(opArgs, args...)
+
+	return Op{
+		scope: s.With(opArgs...),
+	}
+}
+
+type Op struct {
+	scope Scope
+}
+
+func NewOp(name string, args ...any) Op {
+	return Scope{}.Op(name, args...)
+}
+
+func (op Op) Start() Operation {
+	return op.scope.Log(slog.LevelInfo, alog.StartMsg)
+}
+
+func (op Op) Finish(args ...any) Operation {
+	return op.scope.Log(slog.LevelInfo, alog.FinishMsg, args...)
+}
+
+func (op Op) ErrorOutcome(level slog.Level, err error, outcome string, args ...any) Operation {
+	const minArgsAmount = 2
+
+	errArgs := make([]any, 0, len(args)+minArgsAmount)
+
+	errArgs = append(errArgs, alog.ErrorAttr(err), slog.String(alog.OutcomeKey, outcome))
+	errArgs = append(errArgs, args...)
+
+	return op.scope.Log(level, alog.ErrorMsg, errArgs...)
+}
+
+func (op Op) FinishSlow(elapsed time.Duration, args ...any) Operation {
+	finishArgs := make([]any, 0, len(args)+2)
+
+	finishArgs = append(finishArgs, args...)
+	finishArgs = append(finishArgs,
+		slog.Bool(alog.SlowKey, true),
+		slog.Duration(alog.ElapsedKey, elapsed),
+	)
+
+	return op.scope.Log(slog.LevelWarn, alog.FinishMsg, finishArgs...)
+}
+
+func (op Op) Heartbeat(elapsed time.Duration) Operation {
+	return op.scope.Log(slog.LevelInfo, alog.HeartbeatMsg, slog.Duration(alog.ElapsedKey, elapsed))
+}
+
+func (op Op) Op(name string, args ...any) Op {
+	return op.scope.Op(name, args...)
+}
+
+func (op Op) Error(err error, args ...any) Operation {
+	const minArgsAmount = 1
+
+	errArgs := make([]any, 0, len(args)+minArgsAmount)
+
+	errArgs = append(errArgs, alog.ErrorAttr(err))
+	errArgs = append(errArgs, args...)
+
+	return op.scope.Log(slog.LevelError, alog.ErrorMsg, errArgs...)
+}
+
+func (op Op) Debug(msg string, args ...any) Operation {
+	return op.scope.Log(slog.LevelDebug, msg, args...)
+}
+
+func (op Op) Info(msg string, args ...any) Operation {
+	return op.scope.Log(slog.LevelInfo, msg, args...)
+}
+
+func (op Op) Warn(msg string, args ...any) Operation {
+	return op.scope.Log(slog.LevelWarn, msg, args...)
+}
+
+func (op Op) Log(level slog.Level, msg string, args ...any) Operation {
+	return op.scope.Log(level, msg, args...)
+}
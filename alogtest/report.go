@@ -0,0 +1,116 @@
+package alogtest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+const missingRecord = "<missing>"
+
+type mismatch struct {
+	index    int
+	expected string
+	actual   string
+	source   string
+}
+
+func findMismatches(expectedRecords []expectedRecord, actualRecords []logRecord, showSource bool) []mismatch {
+	var mismatches []mismatch
+
+	for i := range max(len(expectedRecords), len(actualRecords)) {
+		m := mismatch{
+			index:    i,
+			expected: missingRecord,
+			actual:   missingRecord,
+		}
+
+		var (
+			expected Source
+			actualPC uintptr
+		)
+
+		if i < len(expectedRecords) {
+			m.expected = strings.TrimSuffix(expectedRecords[i].text, "\n")
+			expected = expectedRecords[i].source
+		}
+
+		if i < len(actualRecords) {
+			m.actual = strings.TrimSuffix(actualRecords[i].text, "\n")
+			actualPC = actualRecords[i].pc
+
+			if showSource {
+				m.source = pcLocation(actualPC)
+			}
+		}
+
+		switch {
+		case m.expected != m.actual:
+		case !expected.match(actualPC):
+			m.expected += " " + expected.String()
+			m.source = pcLocation(actualPC)
+		default:
+			continue
+		}
+
+		mismatches = append(mismatches, m)
+	}
+
+	return mismatches
+}
+
+func errorfMismatches(tester errorfTester, expectedRecords []expectedRecord, actualRecords []logRecord, showSource bool) {
+	if h, ok := tester.(helperTester); ok {
+		h.Helper()
+	}
+
+	mismatches := findMismatches(expectedRecords, actualRecords, showSource)
+	if len(mismatches) == 0 {
+		return
+	}
+
+	tester.Errorf("\n%s", mismatchesTable(mismatches, len(expectedRecords), len(actualRecords)))
+}
+
+func mismatchesTable(mismatches []mismatch, expectedCount, actualCount int) string {
+	bld := new(strings.Builder)
+
+	fmt.Fprintf(bld, "INVALID RECORDS: %d MISMATCHES, %d EXPECTED, %d ACTUAL\n",
+		len(mismatches),
+		expectedCount,
+		actualCount,
+	)
+
+	showSource := slices.ContainsFunc(mismatches, func(m mismatch) bool { return m.source != "" })
+
+	w := tabwriter.NewWriter(bld, 0, 4, 2, ' ', 0)
+
+	if showSource {
+		fmt.Fprintln(w, "INDEX\tEXPECTED\tACTUAL\tSOURCE")
+	} else {
+		fmt.Fprintln(w, "INDEX\tEXPECTED\tACTUAL")
+	}
+
+	for _, m := range mismatches {
+		if showSource {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", m.index, m.expected, m.actual, m.source)
+		} else {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", m.index, m.expected, m.actual)
+		}
+	}
+
+	_ = w.Flush()
+
+	return bld.String()
+}
+
+func pcLocation(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+
+	frame := pcFrame(pc)
+
+	return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+}
@@ -0,0 +1,182 @@
+package alogtest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/amidgo/alog"
+)
+
+type Source struct {
+	// Function matches the end of the fully qualified function name,
+	// e.g. "Test_Source" or "alogtest.Test_Source.func1".
+	Function string
+	// File matches the base name of the file.
+	File string
+	// Marker matches when the source line contains it.
+	Marker string
+	Line   int
+}
+
+func (s Source) isZero() bool {
+	return s == Source{}
+}
+
+func (s Source) match(pc uintptr) bool {
+	if s.isZero() {
+		return true
+	}
+
+	frame := pcFrame(pc)
+
+	if s.Function != "" && !matchFunction(frame.Function, s.Function) {
+		return false
+	}
+
+	if s.File != "" && filepath.Base(frame.File) != s.File {
+		return false
+	}
+
+	if s.Line != 0 && frame.Line != s.Line {
+		return false
+	}
+
+	if s.Marker != "" && !strings.Contains(sourceLine(frame.File, frame.Line), s.Marker) {
+		return false
+	}
+
+	return true
+}
+
+func (s Source) String() string {
+	parts := make([]string, 0, 4)
+
+	if s.Function != "" {
+		parts = append(parts, "function="+s.Function)
+	}
+
+	if s.File != "" {
+		parts = append(parts, "file="+s.File)
+	}
+
+	if s.Line != 0 {
+		parts = append(parts, fmt.Sprintf("line=%d", s.Line))
+	}
+
+	if s.Marker != "" {
+		parts = append(parts, fmt.Sprintf("marker=%q", s.Marker))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func matchFunction(function, suffix string) bool {
+	if function == suffix {
+		return true
+	}
+
+	return strings.HasSuffix(function, "."+suffix) || strings.HasSuffix(function, "/"+suffix)
+}
+
+func pcFrame(pc uintptr) runtime.Frame {
+	fs := runtime.CallersFrames([]uintptr{pc})
+	f, _ := fs.Next()
+
+	return f
+}
+
+func pcString(pc uintptr) string {
+	if pc == 0 {
+		return "<unknown>"
+	}
+
+	frame := pcFrame(pc)
+
+	return fmt.Sprintf("%s %s:%d\n    %s",
+		frame.Function,
+		frame.File,
+		frame.Line,
+		strings.TrimSpace(sourceLine(frame.File, frame.Line)),
+	)
+}
+
+func sourceLine(file string, line int) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for i := 1; scanner.Scan(); i++ {
+		if i == line {
+			return scanner.Text()
+		}
+	}
+
+	return ""
+}
+
+func fatalfInvalidSourceByIndex(tester Tester, index int, expected Source, actualPC uintptr) {
+	tester.Fatalf(
+		"\nINVALID RECORD SOURCE BY %d INDEX\nEXPECTED:\n    %s\nACTUAL:\n    %s\n",
+		index,
+		expected,
+		pcString(actualPC),
+	)
+}
+
+// AssertCallerSource fails the test if a record logged by logFunc has a source
+// other than logFunc, every invalid record is reported if the tester has Errorf.
+func AssertCallerSource(tester Tester, logFunc func(ctx context.Context)) {
+	if h, ok := tester.(helperTester); ok {
+		h.Helper()
+	}
+
+	h := recordsCollector{
+		newHandler: newTextHandler,
+		mutates:    []func(h slog.Handler) slog.Handler{},
+		logs:       &logs{},
+	}
+
+	logFunc(alog.Context(context.Background(), h))
+
+	records := h.logs.Records()
+	if len(records) == 0 {
+		tester.Fatalf("\nNO RECORDS LOGGED\n")
+
+		return
+	}
+
+	expectedFunction := runtime.FuncForPC(reflect.ValueOf(logFunc).Pointer()).Name()
+
+	errorf, hasErrorf := tester.(errorfTester)
+
+	for i, rec := range records {
+		if pcFrame(rec.pc).Function == expectedFunction {
+			continue
+		}
+
+		const format = "\nINVALID CALLER SOURCE BY %d INDEX\nEXPECTED FUNCTION:\n    %s\nACTUAL:\n    %s\nRECORD:\n%s\n"
+
+		args := []any{i, expectedFunction, pcString(rec.pc), recordForTesterMessage(rec.text)}
+
+		if hasErrorf {
+			errorf.Errorf(format, args...)
+
+			continue
+		}
+
+		tester.Fatalf(format, args...)
+
+		return
+	}
+}
@@ -0,0 +1,27 @@
+package alogtest
+
+import "github.com/amidgo/alog"
+
+// Strict fails the test with the stack of every log call and alog.Handler
+// call with a context without a handler until the test ends. It changes the
+// process fallback, so tests calling Strict must not run in parallel.
+func Strict(tester Tester) {
+	if h, ok := tester.(helperTester); ok {
+		h.Helper()
+	}
+
+	report := tester.Fatalf
+	if errorf, ok := tester.(errorfTester); ok {
+		report = errorf.Errorf
+	}
+
+	restore := alog.SetFallback(
+		&alog.FallbackOptions{
+			Report: func(stack []byte) {
+				report("\nLOGGED WITH CONTEXT WITHOUT HANDLER\n%s", stack)
+			},
+		},
+	)
+
+	tester.Cleanup(restore)
+}
@@ -0,0 +1,24 @@
+package alogtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amidgo/alog"
+)
+
+func Test_Strict(t *testing.T) {
+	Strict(t)
+
+	ctx := alog.Context(t.Context(), NewHandler(t, nil, Info("info")))
+
+	alog.Info(ctx, "info")
+}
+
+func Test_Strict_ContextWithoutHandler(t *testing.T) {
+	tester := newFatalfStubTester(t)
+
+	Strict(tester)
+
+	alog.Debug(context.Background(), "lost")
+}
@@ -0,0 +1,244 @@
+package alog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// attrsNode is an immutable layer of attributes or a group added to the
+// context handler. Every node holds the handler installed by Context in
+// base, the layers are applied to it only when an enabled record is logged
+// with the context. The root node adds the trace attributes of the current
+// operation to base, before the layers, so groups do not apply to them. The
+// fallback root node is created by With and WithGroup for contexts without a
+// handler.
+type attrsNode struct {
+	parent   *attrsNode
+	base     slog.Handler
+	byAttrs  bool
+	fallback bool
+	trace    []slog.Attr
+	attrs    []slog.Attr
+	group    string
+
+	once     sync.Once
+	resolved slog.Handler
+}
+
+// AttrsEnabler is implemented by handlers that enable levels by the
+// attributes added with WithAttrs, for example more verbose logging for one
+// operation. alog drops records disabled by the handler installed by Context
+// before applying the context attributes to it, unless EnabledByAttrs
+// returns true, then Enabled is called on the handler with the attributes.
+type AttrsEnabler interface {
+	EnabledByAttrs() bool
+}
+
+func enabledByAttrs(h slog.Handler) bool {
+	enabler, ok := h.(AttrsEnabler)
+
+	return ok && enabler.EnabledByAttrs()
+}
+
+func newRootNode(ctx context.Context, h slog.Handler) *attrsNode {
+	return &attrsNode{
+		base:    h,
+		byAttrs: enabledByAttrs(h),
+		trace:   traceAttrs(ctx),
+	}
+}
+
+func attrsNodeFromContext(ctx context.Context) *attrsNode {
+	node, ok := ctx.Value(handlerKey{}).(*attrsNode)
+	if !ok {
+		node = newRootNode(ctx, fallbackHandler())
+		node.fallback = true
+	}
+
+	return node
+}
+
+// withTrace returns a copy of the node layers on a root node with the trace
+// attributes, they replace the trace attributes of the previous root node.
+func (n *attrsNode) withTrace(trace []slog.Attr) *attrsNode {
+	if n.parent == nil {
+		return &attrsNode{
+			base:     n.base,
+			byAttrs:  n.byAttrs,
+			fallback: n.fallback,
+			trace:    trace,
+		}
+	}
+
+	return &attrsNode{
+		parent:   n.parent.withTrace(trace),
+		base:     n.base,
+		byAttrs:  n.byAttrs,
+		fallback: n.fallback,
+		attrs:    n.attrs,
+		group:    n.group,
+	}
+}
+
+func (n *attrsNode) withAttrs(attrs []slog.Attr) *attrsNode {
+	return &attrsNode{
+		parent:   n,
+		base:     n.base,
+		byAttrs:  n.byAttrs,
+		fallback: n.fallback,
+		attrs:    attrs,
+	}
+}
+
+func (n *attrsNode) withGroup(groupName string) *attrsNode {
+	return &attrsNode{
+		parent:   n,
+		base:     n.base,
+		byAttrs:  n.byAttrs,
+		fallback: n.fallback,
+		group:    groupName,
+	}
+}
+
+// handler applies the node layers to the base handler once and caches the result.
+func (n *attrsNode) handler() slog.Handler {
+	if n.parent == nil && len(n.trace) == 0 {
+		return n.base
+	}
+
+	n.once.Do(n.resolve)
+
+	return n.resolved
+}
+
+func (n *attrsNode) resolve() {
+	if n.parent == nil {
+		n.resolved = n.base.WithAttrs(n.trace)
+
+		return
+	}
+
+	h := n.parent.handler()
+
+	if n.group != "" {
+		n.resolved = h.WithGroup(n.group)
+
+		return
+	}
+
+	n.resolved = h.WithAttrs(n.attrs)
+}
+
+// DisabledRecorder is implemented by handlers that observe records dropped
+// because their level is disabled. Enabled is also called by guards that log
+// nothing, so it cannot tell such records apart.
+type DisabledRecorder interface {
+	RecordDisabled(ctx context.Context, level slog.Level)
+}
+
+func recordDisabled(ctx context.Context, h slog.Handler, level slog.Level) {
+	if recorder, ok := h.(DisabledRecorder); ok {
+		recorder.RecordDisabled(ctx, level)
+	}
+}
+
+// enabledHandler returns the context handler if level is enabled. Enabled is
+// checked on the base handler before the layers are applied, or on the
+// handler with the layers for an AttrsEnabler.
+func enabledHandler(ctx context.Context, level slog.Level) (slog.Handler, bool) {
+	node, ok := ctx.Value(handlerKey{}).(*attrsNode)
+	if !ok {
+		return fallbackEnabledHandler(ctx, level)
+	}
+
+	return node.enabledHandler(ctx, level)
+}
+
+func fallbackEnabledHandler(ctx context.Context, level slog.Level) (slog.Handler, bool) {
+	fallbackHit()
+
+	h := fallbackHandler()
+	if !h.Enabled(ctx, level) {
+		recordDisabled(ctx, h, level)
+
+		return nil, false
+	}
+
+	return h, true
+}
+
+func (n *attrsNode) enabledHandler(ctx context.Context, level slog.Level) (slog.Handler, bool) {
+	if n.fallback {
+		fallbackHit()
+	}
+
+	if !n.byAttrs {
+		if !n.base.Enabled(ctx, level) {
+			recordDisabled(ctx, n.base, level)
+
+			return nil, false
+		}
+
+		return n.handler(), true
+	}
+
+	h := n.handler()
+	if !h.Enabled(ctx, level) {
+		recordDisabled(ctx, h, level)
+
+		return nil, false
+	}
+
+	return h, true
+}
+
+// Attrs returns the attributes added to the context by With and WithAttrs
+// since the handler was installed by Context, attributes added after
+// WithGroup are nested in group attributes as they appear in records.
+func Attrs(ctx context.Context) []slog.Attr {
+	node, ok := ctx.Value(handlerKey{}).(*attrsNode)
+	if !ok {
+		return nil
+	}
+
+	var attrs []slog.Attr
+
+	for ; node.parent != nil; node = node.parent {
+		if node.group == "" {
+			attrs = slices.Concat(node.attrs, attrs)
+
+			continue
+		}
+
+		if len(attrs) > 0 {
+			attrs = []slog.Attr{
+				{Key: node.group, Value: slog.GroupValue(attrs...)},
+			}
+		}
+	}
+
+	return attrs
+}
+
+// Groups returns the group path added to the context by WithGroup since
+// the handler was installed by Context.
+func Groups(ctx context.Context) []string {
+	node, ok := ctx.Value(handlerKey{}).(*attrsNode)
+	if !ok {
+		return nil
+	}
+
+	var groups []string
+
+	for ; node.parent != nil; node = node.parent {
+		if node.group != "" {
+			groups = append(groups, node.group)
+		}
+	}
+
+	slices.Reverse(groups)
+
+	return groups
+}
@@ -0,0 +1,354 @@
+package alog_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+type countingHandler struct {
+	slog.Handler
+	withCalls *atomic.Int64
+}
+
+func newCountingHandler(h slog.Handler) countingHandler {
+	return countingHandler{
+		Handler:   h,
+		withCalls: &atomic.Int64{},
+	}
+}
+
+func (h countingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h.withCalls.Add(1)
+	h.Handler = h.Handler.WithAttrs(attrs)
+
+	return h
+}
+
+func (h countingHandler) WithGroup(name string) slog.Handler {
+	h.withCalls.Add(1)
+	h.Handler = h.Handler.WithGroup(name)
+
+	return h
+}
+
+func Test_With_Lazy(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("info", "a", 1, slog.Group("g", "b", 2, "c", 3)),
+		alogtest.Info("info", "a", 1, slog.Group("g", "b", 2, "c", 3)),
+	)
+
+	h := newCountingHandler(
+		slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo}),
+	)
+
+	ctx := alog.Context(t.Context(), multiHandler{h, handler})
+	ctx = alog.With(ctx, "a", 1)
+	ctx = alog.WithGroup(ctx, "g")
+	ctx = alog.WithAttrs(ctx, slog.Int("b", 2))
+
+	alog.Debug(ctx, "debug")
+
+	if h.withCalls.Load() != 0 {
+		t.Fatalf("attrs applied to the handler for disabled record, %d calls", h.withCalls.Load())
+	}
+
+	alog.Info(ctx, "info", "c", 3)
+	alog.Info(ctx, "info", "c", 3)
+
+	if h.withCalls.Load() != 3 {
+		t.Fatalf("expected 3 handler calls, actual %d", h.withCalls.Load())
+	}
+}
+
+// verboseHandler enables Debug for records with op=verbose.
+type verboseHandler struct {
+	slog.Handler
+	verbose bool
+}
+
+func (h verboseHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.verbose || h.Handler.Enabled(ctx, level)
+}
+
+func (verboseHandler) EnabledByAttrs() bool {
+	return true
+}
+
+func (h verboseHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	for _, attr := range attrs {
+		if attr.Key == alog.OpKey && attr.Value.String() == "verbose" {
+			h.verbose = true
+		}
+	}
+
+	h.Handler = h.Handler.WithAttrs(attrs)
+
+	return h
+}
+
+func (h verboseHandler) WithGroup(name string) slog.Handler {
+	h.Handler = h.Handler.WithGroup(name)
+
+	return h
+}
+
+func Test_With_EnabledByAttrs(t *testing.T) {
+	out := new(strings.Builder)
+
+	ctx := alog.Context(t.Context(), verboseHandler{Handler: slog.NewTextHandler(out, nil)})
+
+	alog.Debug(ctx, "quiet")
+
+	op := alog.Start(ctx, "verbose")
+	op.Debug("verbose")
+
+	if !alog.Handler(op.Context()).Enabled(ctx, slog.LevelDebug) {
+		t.Fatal("operation handler is not enabled at Debug")
+	}
+
+	if strings.Contains(out.String(), "msg=quiet") || !strings.Contains(out.String(), "msg=verbose op=verbose") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func Test_Attrs(t *testing.T) {
+	type attrsTest struct {
+		name           string
+		ctx            func(ctx context.Context) context.Context
+		expectedAttrs  []slog.Attr
+		expectedGroups []string
+	}
+
+	tests := []attrsTest{
+		{
+			name: "no handler",
+			ctx: func(ctx context.Context) context.Context {
+				return ctx
+			},
+		},
+		{
+			name: "without attributes",
+			ctx: func(ctx context.Context) context.Context {
+				return alog.Context(ctx, alogtest.NewHandler(t, nil))
+			},
+		},
+		{
+			name: "attributes without handler",
+			ctx: func(ctx context.Context) context.Context {
+				return alog.With(ctx, "request_id", "1")
+			},
+			expectedAttrs: []slog.Attr{slog.String("request_id", "1")},
+		},
+		{
+			name: "attributes and groups",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = alog.Context(ctx, slog.DiscardHandler)
+				ctx = alog.With(ctx, "request_id", "1")
+				ctx = alog.WithGroup(ctx, "user")
+				ctx = alog.WithAttrs(ctx, slog.Int("id", 2))
+				ctx = alog.WithGroup(ctx, "city")
+				ctx = alog.With(ctx, "name", "Samara", "zip", 443001)
+				ctx = alog.WithGroup(ctx, "empty")
+
+				return ctx
+			},
+			expectedAttrs: []slog.Attr{
+				slog.String("request_id", "1"),
+				slog.Group("user",
+					slog.Int("id", 2),
+					slog.Group("city",
+						slog.String("name", "Samara"),
+						slog.Int("zip", 443001),
+					),
+				),
+			},
+			expectedGroups: []string{"user", "city", "empty"},
+		},
+		{
+			name: "reset by Context",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = alog.With(ctx, "request_id", "1")
+				ctx = alog.WithGroup(ctx, "user")
+
+				return alog.Context(ctx, slog.DiscardHandler)
+			},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			ctx := tst.ctx(t.Context())
+
+			attrs := alog.Attrs(ctx)
+			if !slices.EqualFunc(attrs, tst.expectedAttrs, slog.Attr.Equal) {
+				t.Fatalf("unexpected attrs\nexpected:\n%v\nactual:\n%v", tst.expectedAttrs, attrs)
+			}
+
+			groups := alog.Groups(ctx)
+			if !slices.Equal(groups, tst.expectedGroups) {
+				t.Fatalf("unexpected groups\nexpected:\n%v\nactual:\n%v", tst.expectedGroups, groups)
+			}
+		})
+	}
+}
+
+func Test_Attrs_NotModified(t *testing.T) {
+	ctx := alog.With(t.Context(), "a", 1)
+
+	attrs := alog.Attrs(ctx)
+	attrs[0] = slog.Int("b", 2)
+	_ = append(attrs, slog.Int("c", 3))
+
+	if !slices.EqualFunc(alog.Attrs(ctx), []slog.Attr{slog.Int("a", 1)}, slog.Attr.Equal) {
+		t.Fatalf("context attrs modified: %v", alog.Attrs(ctx))
+	}
+
+	withAttrs := []slog.Attr{slog.Int("a", 1)}
+
+	ctx = alog.WithAttrs(t.Context(), withAttrs...)
+	withAttrs[0] = slog.Int("b", 2)
+
+	if !slices.EqualFunc(alog.Attrs(ctx), []slog.Attr{slog.Int("a", 1)}, slog.Attr.Equal) {
+		t.Fatalf("context attrs modified: %v", alog.Attrs(ctx))
+	}
+}
+
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if !h.Enabled(ctx, level) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hs := make(multiHandler, 0, len(m))
+
+	for _, h := range m {
+		hs = append(hs, h.WithAttrs(attrs))
+	}
+
+	return hs
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	hs := make(multiHandler, 0, len(m))
+
+	for _, h := range m {
+		hs = append(hs, h.WithGroup(name))
+	}
+
+	return hs
+}
+
+func (m multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	for _, h := range m {
+		err := h.Handle(ctx, record.Clone())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var benchmarkDepths = []int{1, 4, 16, 64}
+
+func benchmarkHandler() slog.Handler {
+	return slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo})
+}
+
+// BenchmarkWith compares context attributes applied on an enabled record
+// with attributes applied eagerly by slog.Logger.With on every layer.
+func BenchmarkWith(b *testing.B) {
+	levels := []slog.Level{slog.LevelDebug, slog.LevelInfo}
+
+	for _, depth := range benchmarkDepths {
+		for _, level := range levels {
+			name := fmt.Sprintf("depth=%d/level=%s", depth, level)
+
+			b.Run(name+"/alog", func(b *testing.B) {
+				base := alog.Context(b.Context(), benchmarkHandler())
+
+				b.ReportAllocs()
+
+				for b.Loop() {
+					ctx := base
+
+					for i := range depth {
+						ctx = alog.With(ctx, "layer", i)
+					}
+
+					alog.Log(ctx, level, "msg")
+				}
+			})
+
+			b.Run(name+"/eager", func(b *testing.B) {
+				base := slog.New(benchmarkHandler())
+				ctx := b.Context()
+
+				b.ReportAllocs()
+
+				for b.Loop() {
+					logger := base
+
+					for i := range depth {
+						logger = logger.With("layer", i)
+					}
+
+					logger.Log(ctx, level, "msg")
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkWith_Reuse measures records logged repeatedly with the same context.
+func BenchmarkWith_Reuse(b *testing.B) {
+	for _, depth := range benchmarkDepths {
+		name := fmt.Sprintf("depth=%d", depth)
+
+		b.Run(name+"/alog", func(b *testing.B) {
+			ctx := alog.Context(b.Context(), benchmarkHandler())
+
+			for i := range depth {
+				ctx = alog.With(ctx, "layer", i)
+			}
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				alog.Info(ctx, "msg")
+			}
+		})
+
+		b.Run(name+"/eager", func(b *testing.B) {
+			logger := slog.New(benchmarkHandler())
+			ctx := b.Context()
+
+			for i := range depth {
+				logger = logger.With("layer", i)
+			}
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				logger.InfoContext(ctx, "msg")
+			}
+		})
+	}
+}
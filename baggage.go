@@ -0,0 +1,227 @@
+package alog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const BaggageHeader = "baggage"
+
+// Limits of the W3C baggage header, members over the limits are not encoded.
+const (
+	MaxBaggageMembers = 180
+	MaxBaggageBytes   = 8192
+)
+
+// Carrier gets and sets string headers of requests to other services.
+type Carrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+type HeaderCarrier http.Header
+
+func (c HeaderCarrier) Get(key string) string {
+	return http.Header(c).Get(key)
+}
+
+func (c HeaderCarrier) Set(key, value string) {
+	http.Header(c).Set(key, value)
+}
+
+type MapCarrier map[string]string
+
+func (c MapCarrier) Get(key string) string {
+	return c[key]
+}
+
+func (c MapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+type propagatedValue struct {
+	value any
+}
+
+func (v propagatedValue) LogValue() slog.Value {
+	return slog.AnyValue(v.value)
+}
+
+// Propagate returns an attribute marked to be sent to other services by
+// InjectBaggage, it is logged as slog.Any(key, value).
+func Propagate(key string, value any) slog.Attr {
+	return slog.Any(key, propagatedValue{value: value})
+}
+
+func isPropagated(attr slog.Attr) bool {
+	if attr.Value.Kind() != slog.KindLogValuer {
+		return false
+	}
+
+	_, ok := attr.Value.LogValuer().(propagatedValue)
+
+	return ok
+}
+
+// InjectBaggage sets the baggage header of carrier to the propagated attributes of ctx.
+func InjectBaggage(ctx context.Context, carrier Carrier) {
+	baggage := EncodeBaggage(Attrs(ctx))
+	if baggage == "" {
+		return
+	}
+
+	carrier.Set(BaggageHeader, baggage)
+}
+
+// ExtractBaggage adds the members of the carrier baggage header to ctx as propagated attributes.
+func ExtractBaggage(ctx context.Context, carrier Carrier) context.Context {
+	return WithAttrs(ctx, DecodeBaggage(carrier.Get(BaggageHeader))...)
+}
+
+// EncodeBaggage encodes the top-level attributes marked by Propagate in
+// the W3C baggage format. The last attribute wins for duplicated keys,
+// attributes with keys that are not valid tokens or with group values are
+// skipped, as well as members over MaxBaggageMembers and MaxBaggageBytes.
+func EncodeBaggage(attrs []slog.Attr) string {
+	members := make([]string, 0)
+	indexes := make(map[string]int)
+
+	for _, attr := range attrs {
+		if !isPropagated(attr) || !isBaggageKey(attr.Key) {
+			continue
+		}
+
+		value := attr.Value.Resolve()
+		if value.Kind() == slog.KindGroup {
+			continue
+		}
+
+		member := attr.Key + "=" + escapeBaggageValue(value.String())
+
+		if i, ok := indexes[attr.Key]; ok {
+			members[i] = member
+
+			continue
+		}
+
+		indexes[attr.Key] = len(members)
+		members = append(members, member)
+	}
+
+	bld := new(strings.Builder)
+
+	count := 0
+
+	for _, member := range members {
+		size := len(member)
+		if count > 0 {
+			size++
+		}
+
+		if count == MaxBaggageMembers || bld.Len()+size > MaxBaggageBytes {
+			continue
+		}
+
+		if count > 0 {
+			bld.WriteByte(',')
+		}
+
+		bld.WriteString(member)
+
+		count++
+	}
+
+	return bld.String()
+}
+
+// DecodeBaggage decodes baggage members to attributes marked by Propagate,
+// member properties and invalid members are skipped.
+func DecodeBaggage(baggage string) []slog.Attr {
+	var attrs []slog.Attr
+
+	for member := range strings.SplitSeq(baggage, ",") {
+		member, _, _ = strings.Cut(member, ";")
+
+		key, value, ok := strings.Cut(member, "=")
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		if !isBaggageKey(key) {
+			continue
+		}
+
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+
+		attrs = append(attrs, Propagate(key, value))
+	}
+
+	return attrs
+}
+
+func isBaggageKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i := range len(key) {
+		if !isTokenChar(key[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isTokenChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	default:
+		return strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+	}
+}
+
+func escapeBaggageValue(value string) string {
+	const hex = "0123456789ABCDEF"
+
+	bld := new(strings.Builder)
+
+	for i := range len(value) {
+		c := value[i]
+
+		if isBaggageOctet(c) {
+			bld.WriteByte(c)
+
+			continue
+		}
+
+		bld.WriteByte('%')
+		bld.WriteByte(hex[c>>4])
+		bld.WriteByte(hex[c&0xF])
+	}
+
+	return bld.String()
+}
+
+// isBaggageOctet reports whether c is allowed unescaped in a baggage value,
+// the percent sign is escaped to keep values decodable.
+func isBaggageOctet(c byte) bool {
+	switch {
+	case c == '!':
+		return true
+	case 0x23 <= c && c <= 0x2B:
+		return c != '%'
+	case 0x2D <= c && c <= 0x3A, 0x3C <= c && c <= 0x5B, 0x5D <= c && c <= 0x7E:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,149 @@
+package alog_test
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func Test_EncodeBaggage(t *testing.T) {
+	type encodeTest struct {
+		name     string
+		attrs    []slog.Attr
+		expected string
+	}
+
+	tests := []encodeTest{
+		{
+			name: "only propagated",
+			attrs: []slog.Attr{
+				alog.Propagate("tenant", "acme"),
+				slog.String("password", "secret"),
+				alog.Propagate("user_id", 100),
+			},
+			expected: "tenant=acme,user_id=100",
+		},
+		{
+			name: "escaped values",
+			attrs: []slog.Attr{
+				alog.Propagate("query", `a b,c;d=e"f\g%h`),
+				alog.Propagate("city", "Самара"),
+			},
+			expected: "query=a%20b%2Cc%3Bd=e%22f%5Cg%25h,city=%D0%A1%D0%B0%D0%BC%D0%B0%D1%80%D0%B0",
+		},
+		{
+			name: "last duplicate wins",
+			attrs: []slog.Attr{
+				alog.Propagate("tenant", "acme"),
+				alog.Propagate("user_id", 1),
+				alog.Propagate("tenant", "globex"),
+			},
+			expected: "tenant=globex,user_id=1",
+		},
+		{
+			name: "invalid keys and groups skipped",
+			attrs: []slog.Attr{
+				alog.Propagate("bad key", "value"),
+				alog.Propagate("", "value"),
+				alog.Propagate("group", slog.GroupValue(slog.Int("a", 1))),
+				alog.Propagate("ok", "value"),
+			},
+			expected: "ok=value",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			baggage := alog.EncodeBaggage(tst.attrs)
+			if baggage != tst.expected {
+				t.Fatalf("unexpected baggage\nexpected:\n%s\nactual:\n%s", tst.expected, baggage)
+			}
+		})
+	}
+}
+
+func Test_EncodeBaggage_Limits(t *testing.T) {
+	attrs := make([]slog.Attr, 0, alog.MaxBaggageMembers+1)
+
+	for i := range alog.MaxBaggageMembers + 1 {
+		attrs = append(attrs, alog.Propagate(fmt.Sprintf("k%d", i), i))
+	}
+
+	baggage := alog.EncodeBaggage(attrs)
+
+	members := strings.Split(baggage, ",")
+	if len(members) != alog.MaxBaggageMembers {
+		t.Fatalf("expected %d members, actual %d", alog.MaxBaggageMembers, len(members))
+	}
+
+	baggage = alog.EncodeBaggage([]slog.Attr{
+		alog.Propagate("big", strings.Repeat("a", alog.MaxBaggageBytes)),
+		alog.Propagate("small", "value"),
+	})
+
+	if baggage != "small=value" {
+		t.Fatalf("unexpected baggage %q", baggage)
+	}
+}
+
+func Test_DecodeBaggage(t *testing.T) {
+	baggage := " tenant = acme ,query=a%20b%2Cc;prop=1,invalid,bad key=1,city=%D0%A1%D0%B0%D0%BC%D0%B0%D1%80%D0%B0,broken=%zz"
+
+	expected := []slog.Attr{
+		alog.Propagate("tenant", "acme"),
+		alog.Propagate("query", "a b,c"),
+		alog.Propagate("city", "Самара"),
+	}
+
+	attrs := alog.DecodeBaggage(baggage)
+	if !slices.EqualFunc(attrs, expected, slog.Attr.Equal) {
+		t.Fatalf("unexpected attrs\nexpected:\n%v\nactual:\n%v", expected, attrs)
+	}
+}
+
+func Test_Baggage_HTTP(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("request", "tenant", "acme", "user_id", "100", "local", true),
+	)
+
+	ctx := alog.With(t.Context(), alog.Propagate("tenant", "acme"), "password", "secret")
+	ctx = alog.WithAttrs(ctx, alog.Propagate("user_id", 100))
+
+	header := http.Header{}
+
+	alog.InjectBaggage(ctx, alog.HeaderCarrier(header))
+
+	if header.Get(alog.BaggageHeader) != "tenant=acme,user_id=100" {
+		t.Fatalf("unexpected baggage header %q", header.Get(alog.BaggageHeader))
+	}
+
+	received := alog.Context(t.Context(), handler)
+	received = alog.ExtractBaggage(received, alog.HeaderCarrier(header))
+
+	alog.Info(received, "request", "local", true)
+
+	carrier := alog.MapCarrier{}
+
+	alog.InjectBaggage(received, carrier)
+
+	if carrier[alog.BaggageHeader] != "tenant=acme,user_id=100" {
+		t.Fatalf("extracted baggage is not propagated, %q", carrier[alog.BaggageHeader])
+	}
+}
+
+func Test_InjectBaggage_Empty(t *testing.T) {
+	carrier := alog.MapCarrier{}
+
+	alog.InjectBaggage(alog.With(t.Context(), "password", "secret"), carrier)
+
+	if _, ok := carrier[alog.BaggageHeader]; ok {
+		t.Fatal("baggage header set without propagated attributes")
+	}
+}
@@ -0,0 +1,213 @@
+package alog
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+)
+
+type channelsKey struct{}
+
+// channels are the named channel nodes, the map is copied on every change.
+type channels map[string]*attrsNode
+
+func channelsFromContext(ctx context.Context) channels {
+	chs, _ := ctx.Value(channelsKey{}).(channels)
+
+	return chs
+}
+
+// ContextNamed installs h as the handler of the named channel, the empty
+// name is the default channel installed by Context.
+func ContextNamed(ctx context.Context, name string, h slog.Handler) context.Context {
+	if h == nil {
+		return ctx
+	}
+
+	if name == "" {
+		return Context(ctx, h)
+	}
+
+	return withChannel(ctx, name, newRootNode(ctx, h))
+}
+
+func withChannel(ctx context.Context, name string, node *attrsNode) context.Context {
+	prev := channelsFromContext(ctx)
+
+	chs := make(channels, len(prev)+1)
+
+	maps.Copy(chs, prev)
+
+	chs[name] = node
+
+	return context.WithValue(ctx, channelsKey{}, chs)
+}
+
+func withChannels(ctx context.Context, apply func(node *attrsNode) *attrsNode) context.Context {
+	prev := channelsFromContext(ctx)
+	if len(prev) == 0 {
+		return ctx
+	}
+
+	chs := make(channels, len(prev))
+
+	for name, node := range prev {
+		chs[name] = apply(node)
+	}
+
+	return context.WithValue(ctx, channelsKey{}, chs)
+}
+
+// ChannelLogger logs records to a named channel of the context.
+type ChannelLogger struct {
+	ctx  context.Context
+	name string
+}
+
+// Channel returns the logger of the named channel, records are logged to the
+// default channel if the context has no channel with the name.
+func Channel(ctx context.Context, name string) ChannelLogger {
+	return ChannelLogger{
+		ctx:  ctx,
+		name: name,
+	}
+}
+
+func (c ChannelLogger) node() (*attrsNode, bool) {
+	if c.name != "" {
+		node, ok := channelsFromContext(c.ctx)[c.name]
+		if ok {
+			return node, true
+		}
+	}
+
+	node, ok := c.ctx.Value(handlerKey{}).(*attrsNode)
+
+	return node, ok
+}
+
+// Handler returns the channel handler with the context attributes and groups.
+func (c ChannelLogger) Handler() slog.Handler {
+	node, ok := c.node()
+	if !ok {
+		fallbackHit()
+
+		return fallbackHandler()
+	}
+
+	if node.fallback {
+		fallbackHit()
+	}
+
+	return node.handler()
+}
+
+// With adds attributes to the context for the channel only, a missing
+// channel is created from the default channel, so its records still go to
+// the default handler.
+func (c ChannelLogger) With(args ...any) context.Context {
+	return c.withAttrs(argsToAttrSlice(args))
+}
+
+func (c ChannelLogger) WithAttrs(attrs ...slog.Attr) context.Context {
+	return c.withAttrs(slices.Clone(attrs))
+}
+
+func (c ChannelLogger) withAttrs(attrs []slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return c.ctx
+	}
+
+	return c.withNode(c.channelNode().withAttrs(attrs))
+}
+
+func (c ChannelLogger) WithGroup(groupName string) context.Context {
+	if groupName == "" {
+		return c.ctx
+	}
+
+	return c.withNode(c.channelNode().withGroup(groupName))
+}
+
+func (c ChannelLogger) channelNode() *attrsNode {
+	node, ok := c.node()
+	if !ok {
+		return attrsNodeFromContext(c.ctx)
+	}
+
+	return node
+}
+
+func (c ChannelLogger) withNode(node *attrsNode) context.Context {
+	if c.name == "" {
+		return context.WithValue(c.ctx, handlerKey{}, node)
+	}
+
+	return withChannel(c.ctx, c.name, node)
+}
+
+func (c ChannelLogger) Debug(msg string, args ...any) {
+	c.log(slog.LevelDebug, msg, args)
+}
+
+func (c ChannelLogger) Info(msg string, args ...any) {
+	c.log(slog.LevelInfo, msg, args)
+}
+
+func (c ChannelLogger) Warn(msg string, args ...any) {
+	c.log(slog.LevelWarn, msg, args)
+}
+
+func (c ChannelLogger) Error(msg string, args ...any) {
+	c.log(slog.LevelError, msg, args)
+}
+
+func (c ChannelLogger) Log(level slog.Level, msg string, args ...any) {
+	c.log(level, msg, args)
+}
+
+func (c ChannelLogger) LogAttrs(level slog.Level, msg string, attrs ...slog.Attr) {
+	c.logAttrs(level, msg, attrs)
+}
+
+func (c ChannelLogger) logAttrs(level slog.Level, msg string, attrs []slog.Attr) {
+	h, enabled := c.enabledHandler(level)
+	if !enabled {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), level, msg, callerPC(2))
+
+	r.AddAttrs(attrs...)
+
+	_ = h.Handle(c.ctx, r)
+}
+
+func (c ChannelLogger) log(level slog.Level, msg string, args []any) {
+	level, args, skip := applyLoggedPolicy(c.ctx, level, args)
+	if skip {
+		return
+	}
+
+	h, enabled := c.enabledHandler(level)
+	if !enabled {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), level, msg, callerPC(2))
+
+	addArgs(&r, args)
+
+	_ = h.Handle(c.ctx, r)
+}
+
+func (c ChannelLogger) enabledHandler(level slog.Level) (slog.Handler, bool) {
+	node, ok := c.node()
+	if !ok {
+		return fallbackEnabledHandler(c.ctx, level)
+	}
+
+	return node.enabledHandler(c.ctx, level)
+}
@@ -0,0 +1,95 @@
+package alog_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func Test_Channel(t *testing.T) {
+	app := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("app", "request_id", "1"),
+		alogtest.Info("missing channel", "request_id", "1"),
+		alogtest.Warn("app group", "request_id", "1", slog.Group("g", "a", 1)),
+	)
+
+	audit := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("audit", "request_id", "1", "actor", "admin", "action", "delete"),
+		alogtest.Error("audit group", "request_id", "1", "actor", "admin", slog.Group("g", "a", 1)),
+	)
+
+	ctx := alog.Context(t.Context(), app)
+	ctx = alog.ContextNamed(ctx, "audit", audit)
+	ctx = alog.With(ctx, "request_id", "1")
+	ctx = alog.Channel(ctx, "audit").With("actor", "admin")
+
+	alog.Info(ctx, "app")
+	alog.Channel(ctx, "audit").Info("audit", "action", "delete")
+	alog.Channel(ctx, "access").Info("missing channel")
+
+	ctx = alog.WithGroup(ctx, "g")
+
+	alog.Warn(ctx, "app group", "a", 1)
+	alog.Channel(ctx, "audit").Error("audit group", "a", 1)
+}
+
+func Test_Channel_MissingWith(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("access", slog.Group("http", "path", "/users")),
+		alogtest.Info("app"),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.Channel(ctx, "access").WithGroup("http")
+	ctx = alog.Channel(ctx, "access").WithAttrs(slog.String("path", "/users"))
+
+	alog.Channel(ctx, "access").Info("access")
+	alog.Info(ctx, "app")
+}
+
+func Test_Channel_Default(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Debug("debug", "a", 1),
+		alogtest.Warn("warn", "a", 1),
+		alogtest.Info("log attrs", "a", 1, "b", 2),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.Channel(ctx, "").With("a", 1)
+
+	alog.Channel(ctx, "").Debug("debug")
+	alog.Channel(ctx, "").Log(slog.LevelWarn, "warn")
+	alog.Channel(ctx, "").LogAttrs(slog.LevelInfo, "log attrs", slog.Int("b", 2))
+}
+
+func Test_Channel_Handler(t *testing.T) {
+	ctx := alog.Context(t.Context(), slog.DiscardHandler)
+
+	if alog.Channel(ctx, "audit").Handler() != slog.DiscardHandler {
+		t.Fatal("missing channel handler is not the default handler")
+	}
+
+	if alog.Channel(t.Context(), "audit").Handler() != slog.Default().Handler() {
+		t.Fatal("missing channel handler without handlers is not slog default handler")
+	}
+}
+
+func Test_Channel_CallerSource(t *testing.T) {
+	alogtest.AssertCallerSource(t, func(ctx context.Context) {
+		ctx = alog.ContextNamed(ctx, "", alog.Handler(ctx))
+
+		alog.Channel(ctx, "audit").Debug("debug")
+		alog.Channel(ctx, "audit").Info("info")
+		alog.Channel(ctx, "audit").Warn("warn")
+		alog.Channel(ctx, "audit").Error("error")
+		alog.Channel(ctx, "audit").Log(slog.LevelInfo, "log")
+		alog.Channel(ctx, "audit").LogAttrs(slog.LevelInfo, "log attrs")
+	})
+}
@@ -0,0 +1,149 @@
+package alog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+type Classification struct {
+	Level   slog.Level
+	Outcome string
+}
+
+type classifierRule struct {
+	match          func(error) bool
+	classification Classification
+}
+
+// Classifier maps errors to levels and outcomes for Err and Operation.Error,
+// rules are checked in registration order.
+type Classifier struct {
+	mu    sync.RWMutex
+	rules []classifierRule
+}
+
+func NewClassifier() *Classifier {
+	return &Classifier{}
+}
+
+func (c *Classifier) Is(target error, level slog.Level, outcome string) *Classifier {
+	return c.Func(
+		func(err error) bool {
+			return errors.Is(err, target)
+		},
+		level,
+		outcome,
+	)
+}
+
+func (c *Classifier) Func(match func(error) bool, level slog.Level, outcome string) *Classifier {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rules = append(c.rules,
+		classifierRule{
+			match: match,
+			classification: Classification{
+				Level:   level,
+				Outcome: outcome,
+			},
+		},
+	)
+
+	return c
+}
+
+func ClassifyAs[E error](c *Classifier, level slog.Level, outcome string) *Classifier {
+	return c.Func(
+		func(err error) bool {
+			var target E
+
+			return errors.As(err, &target)
+		},
+		level,
+		outcome,
+	)
+}
+
+func (c *Classifier) Classify(err error) (Classification, bool) {
+	if c == nil || err == nil {
+		return Classification{}, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, rule := range c.rules {
+		if rule.match(err) {
+			return rule.classification, true
+		}
+	}
+
+	return Classification{}, false
+}
+
+type classifierKey struct{}
+
+func WithClassifier(ctx context.Context, c *Classifier) context.Context {
+	if c == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, classifierKey{}, c)
+}
+
+func classifierFromContext(ctx context.Context) *Classifier {
+	c, _ := ctx.Value(classifierKey{}).(*Classifier)
+
+	return c
+}
+
+func classify(ctx context.Context, err error, canceledLevel slog.Level) (Classification, bool) {
+	classification, ok := classifierFromContext(ctx).Classify(err)
+	if ok {
+		return classification, true
+	}
+
+	outcome, canceled := cancellationOutcome(err)
+	if canceled {
+		return Classification{
+			Level:   canceledLevel,
+			Outcome: outcome,
+		}, true
+	}
+
+	return Classification{Level: slog.LevelError}, false
+}
+
+// Err logs err with the level and outcome of the context Classifier,
+// context cancellation errors are logged at Warn and other errors at Error.
+// It returns err, marked as logged if the context has a LoggedPolicy.
+// Err is the supported way to log errors that may be context cancellations,
+// Error and Log use the given level for them.
+func Err(ctx context.Context, err error, msg string, args ...any) error {
+	classification, _ := classify(ctx, err, slog.LevelWarn)
+
+	rec := newLoggedRecord(loggedPolicyFromContext(ctx), err, classification.Level)
+	if rec.skip {
+		return rec.err
+	}
+
+	buf := getAttrs()
+	defer putAttrs(buf)
+
+	attrs := rec.appendAttrs(*buf)
+
+	if classification.Outcome != "" {
+		attrs = append(attrs, slog.String(OutcomeKey, classification.Outcome))
+	}
+
+	attrs = appendArgs(attrs, args)
+
+	alogAttrs(ctx, rec.level, msg, attrs...)
+
+	*buf = attrs
+
+	return rec.err
+}
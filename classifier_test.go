@@ -0,0 +1,167 @@
+package alog_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+var errNotFound = errors.New("not found")
+
+type validationError struct {
+	field string
+}
+
+func (e *validationError) Error() string {
+	return "invalid " + e.field
+}
+
+func newTestClassifier() *alog.Classifier {
+	c := alog.NewClassifier().
+		Is(errNotFound, slog.LevelWarn, "not_found").
+		Func(
+			func(err error) bool {
+				return errors.Is(err, io.EOF)
+			},
+			slog.LevelDebug,
+			"",
+		)
+
+	return alog.ClassifyAs[*validationError](c, slog.LevelInfo, "invalid")
+}
+
+func Test_Classifier_Classify(t *testing.T) {
+	type classifyTest struct {
+		name                   string
+		classifier             *alog.Classifier
+		err                    error
+		expectedClassification alog.Classification
+		expectedOk             bool
+	}
+
+	tests := []classifyTest{
+		{
+			name:                   "errors.Is",
+			classifier:             newTestClassifier(),
+			err:                    fmt.Errorf("get user: %w", errNotFound),
+			expectedClassification: alog.Classification{Level: slog.LevelWarn, Outcome: "not_found"},
+			expectedOk:             true,
+		},
+		{
+			name:                   "errors.As",
+			classifier:             newTestClassifier(),
+			err:                    fmt.Errorf("create user: %w", &validationError{field: "name"}),
+			expectedClassification: alog.Classification{Level: slog.LevelInfo, Outcome: "invalid"},
+			expectedOk:             true,
+		},
+		{
+			name:                   "func",
+			classifier:             newTestClassifier(),
+			err:                    io.EOF,
+			expectedClassification: alog.Classification{Level: slog.LevelDebug},
+			expectedOk:             true,
+		},
+		{
+			name:                   "first matched rule",
+			classifier:             newTestClassifier(),
+			err:                    errors.Join(io.EOF, errNotFound),
+			expectedClassification: alog.Classification{Level: slog.LevelWarn, Outcome: "not_found"},
+			expectedOk:             true,
+		},
+		{
+			name:       "not matched",
+			classifier: newTestClassifier(),
+			err:        io.ErrUnexpectedEOF,
+		},
+		{
+			name:       "nil error",
+			classifier: newTestClassifier(),
+			err:        nil,
+		},
+		{
+			name:       "nil classifier",
+			classifier: nil,
+			err:        errNotFound,
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			classification, ok := tst.classifier.Classify(tst.err)
+
+			if ok != tst.expectedOk || classification != tst.expectedClassification {
+				t.Fatalf("unexpected classification\nexpected:\n%+v %t\nactual:\n%+v %t",
+					tst.expectedClassification, tst.expectedOk,
+					classification, ok,
+				)
+			}
+		})
+	}
+}
+
+func Test_Err(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Warn("get user", alog.ErrorKey, errNotFound.Error(), alog.OutcomeKey, "not_found", "id", 1),
+		alogtest.Info("create user", alog.ErrorKey, "invalid name", alog.OutcomeKey, "invalid"),
+		alogtest.Debug("read", alog.ErrorKey, io.EOF.Error()),
+		alogtest.Warn("wait", alog.ErrorKey, context.Canceled.Error(), alog.OutcomeKey, alog.OutcomeCanceled),
+		alogtest.Error("query", alog.ErrorKey, io.ErrUnexpectedEOF.Error()),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.WithClassifier(ctx, newTestClassifier())
+
+	alog.Err(ctx, errNotFound, "get user", "id", 1)
+	alog.Err(ctx, &validationError{field: "name"}, "create user")
+	alog.Err(ctx, io.EOF, "read")
+	alog.Err(ctx, context.Canceled, "wait")
+	alog.Err(ctx, io.ErrUnexpectedEOF, "query")
+}
+
+func Test_Err_WithoutClassifier(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Error("get user", alog.ErrorKey, errNotFound.Error()),
+		alogtest.Warn("wait", alog.ErrorKey, context.DeadlineExceeded.Error(), alog.OutcomeKey, alog.OutcomeTimeout),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	if alog.WithClassifier(ctx, nil) != ctx {
+		t.Fatal("context has been modified")
+	}
+
+	alog.Err(ctx, errNotFound, "get user")
+	alog.Err(ctx, context.DeadlineExceeded, "wait")
+}
+
+func Test_Err_CallerSource(t *testing.T) {
+	alogtest.AssertCallerSource(t, func(ctx context.Context) {
+		alog.Err(ctx, io.EOF, "read")
+	})
+}
+
+func Test_Operation_Error_Classifier(t *testing.T) {
+	op := alogtest.NewOp("get user")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.ErrorOutcome(slog.LevelWarn, errNotFound, "not_found", "id", 1),
+		op.Error(io.EOF).At(alogtest.Source{Marker: "aop.Error(io.EOF)"}),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.WithClassifier(ctx, alog.NewClassifier().Is(errNotFound, slog.LevelWarn, "not_found"))
+
+	aop := alog.Start(ctx, "get user")
+	aop.Error(errNotFound, "id", 1)
+	aop.Error(io.EOF)
+}
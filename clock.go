@@ -0,0 +1,35 @@
+package alog
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{timer: time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	timer *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t systemTimer) Stop() bool {
+	return t.timer.Stop()
+}
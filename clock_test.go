@@ -0,0 +1,117 @@
+package alog_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amidgo/alog"
+)
+
+type fakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	timers  []*fakeTimer
+	created int
+}
+
+func newFakeClock() *fakeClock {
+	c := &fakeClock{
+		now: time.Date(2023, time.August, 8, 20, 14, 6, 0, time.UTC),
+	}
+
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) alog.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{
+		clock:    c,
+		deadline: c.now.Add(d),
+		c:        make(chan time.Time, 1),
+	}
+
+	c.timers = append(c.timers, t)
+	c.created++
+	c.cond.Broadcast()
+
+	return t
+}
+
+// WaitTimers blocks until n timers have been created since the clock creation.
+func (c *fakeClock) WaitTimers(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.created < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	active := c.timers[:0]
+
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			active = append(active, t)
+
+			continue
+		}
+
+		t.c <- c.now
+	}
+
+	c.timers = active
+}
+
+func (c *fakeClock) stop(t *fakeTimer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.timers {
+		if c.timers[i] == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.clock.stop(t)
+}
+
+func (c *fakeClock) active() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
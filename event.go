@@ -0,0 +1,134 @@
+package alog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+type eventKey struct{}
+
+type counter struct {
+	key   string
+	value int64
+}
+
+type event struct {
+	mu       sync.Mutex
+	attrs    []slog.Attr
+	counters []counter
+	parent   *event
+	rollUp   bool
+	endOnce  sync.Once
+}
+
+func newEvent(parent *event, rollUp bool) *event {
+	return &event{
+		parent: parent,
+		rollUp: rollUp,
+	}
+}
+
+func eventFromContext(ctx context.Context) *event {
+	ev, _ := ctx.Value(eventKey{}).(*event)
+
+	return ev
+}
+
+// Add adds attributes to the final record of the current operation,
+// an attribute replaces the previously added one with the same key.
+func Add(ctx context.Context, args ...any) {
+	ev := eventFromContext(ctx)
+	if ev == nil {
+		return
+	}
+
+	ev.add(argsToAttrSlice(args))
+}
+
+// Count adds n to the operation counter with the given key,
+// counters are logged on the final record of the current operation.
+func Count(ctx context.Context, key string, n int64) {
+	ev := eventFromContext(ctx)
+	if ev == nil {
+		return
+	}
+
+	ev.count(key, n)
+}
+
+func (ev *event) add(attrs []slog.Attr) {
+	ev.mu.Lock()
+	defer ev.mu.Unlock()
+
+	for _, attr := range attrs {
+		i := slices.IndexFunc(ev.attrs, func(a slog.Attr) bool { return a.Key == attr.Key })
+		if i >= 0 {
+			ev.attrs[i] = attr
+
+			continue
+		}
+
+		ev.attrs = append(ev.attrs, attr)
+	}
+}
+
+func (ev *event) count(key string, n int64) {
+	ev.mu.Lock()
+	defer ev.mu.Unlock()
+
+	for i := range ev.counters {
+		if ev.counters[i].key == key {
+			ev.counters[i].value += n
+
+			return
+		}
+	}
+
+	ev.counters = append(ev.counters,
+		counter{
+			key:   key,
+			value: n,
+		},
+	)
+}
+
+func (ev *event) end() {
+	if ev == nil {
+		return
+	}
+
+	ev.endOnce.Do(ev.rollUpCounters)
+}
+
+func (ev *event) rollUpCounters() {
+	if !ev.rollUp || ev.parent == nil {
+		return
+	}
+
+	ev.mu.Lock()
+	counters := slices.Clone(ev.counters)
+	ev.mu.Unlock()
+
+	for _, c := range counters {
+		ev.parent.count(c.key, c.value)
+	}
+}
+
+func (ev *event) appendAttrs(attrs []slog.Attr) []slog.Attr {
+	if ev == nil {
+		return attrs
+	}
+
+	ev.mu.Lock()
+	defer ev.mu.Unlock()
+
+	attrs = append(attrs, ev.attrs...)
+
+	for _, c := range ev.counters {
+		attrs = append(attrs, slog.Int64(c.key, c.value))
+	}
+
+	return attrs
+}
@@ -0,0 +1,131 @@
+package alog_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func Test_Add_Count(t *testing.T) {
+	op := alogtest.NewOp("query")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Finish(
+			"status", "ok",
+			"cache_hit", false,
+			"table", "users",
+			"rows_scanned", int64(5),
+			"retries", int64(1),
+		),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	aop := alog.Start(ctx, "query")
+	ctx = aop.Context()
+
+	alog.Add(ctx, "cache_hit", true, "table", "users")
+	alog.Count(ctx, "rows_scanned", 3)
+	alog.Count(ctx, "retries", 1)
+	alog.Count(ctx, "rows_scanned", 2)
+	alog.Add(ctx, "cache_hit", false)
+
+	aop.FinishWith("status", "ok")
+}
+
+func Test_Add_Error(t *testing.T) {
+	op := alogtest.NewOp("query")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Error(nil, "reason", "db", "upstream_ms", 10),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	aop := alog.Start(ctx, "query")
+
+	alog.Add(aop.Context(), "upstream_ms", 10)
+
+	aop.Error(nil, "reason", "db")
+}
+
+func Test_Add_WithoutOperation(t *testing.T) {
+	handler := alogtest.NewHandler(t, (*alogtest.AssertOptions)(nil))
+
+	ctx := alog.Context(t.Context(), handler)
+
+	alog.Add(ctx, "key", "value")
+	alog.Count(ctx, "counter", 1)
+}
+
+func Test_Count_RollUp(t *testing.T) {
+	parent := alogtest.NewOp("parent")
+	rollUp := parent.Op("roll up")
+	isolated := parent.Op("isolated")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		parent.Start(),
+		rollUp.Start(),
+		rollUp.Finish("rows", int64(2)),
+		rollUp.Error(nil, "rows", int64(2)),
+		isolated.Start(),
+		isolated.Finish("rows", int64(10)),
+		parent.Finish("rows", int64(3)),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	parentOp := alog.Start(ctx, "parent")
+	alog.Count(parentOp.Context(), "rows", 1)
+
+	rollUpOp := alog.StartWithOptions(parentOp.Context(), "roll up", &alog.StartOptions{RollUp: true})
+	alog.Count(rollUpOp.Context(), "rows", 2)
+	rollUpOp.Finish()
+	rollUpOp.Error(nil)
+
+	isolatedOp := alog.Start(parentOp.Context(), "isolated")
+	alog.Count(isolatedOp.Context(), "rows", 10)
+	isolatedOp.Finish()
+
+	parentOp.Finish()
+}
+
+func Test_Count_Concurrent(t *testing.T) {
+	const goroutines = 100
+
+	op := alogtest.NewOp("parallel")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Finish("last", "value", "items", int64(goroutines)),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	aop := alog.Start(ctx, "parallel")
+
+	wg := sync.WaitGroup{}
+
+	wg.Add(goroutines)
+
+	for range goroutines {
+		go func() {
+			defer wg.Done()
+
+			alog.Count(aop.Context(), "items", 1)
+			alog.Add(aop.Context(), "last", "value")
+		}()
+	}
+
+	wg.Wait()
+
+	aop.Finish()
+}
@@ -0,0 +1,19 @@
+package alog
+
+func SetNewRecordID(f func() string) (restore func()) {
+	prev := newRecordID
+	newRecordID = f
+
+	return func() {
+		newRecordID = prev
+	}
+}
+
+func SetNewTraceIDs(traceID func() [16]byte, spanID func() [8]byte) (restore func()) {
+	prevTraceID, prevSpanID := newTraceID, newSpanID
+	newTraceID, newSpanID = traceID, spanID
+
+	return func() {
+		newTraceID, newSpanID = prevTraceID, prevSpanID
+	}
+}
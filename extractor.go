@@ -0,0 +1,102 @@
+package alog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Extractor returns attributes from values stored in the context by other packages.
+type Extractor func(ctx context.Context) []slog.Attr
+
+// Extractors is a registry of extractors, it is safe for concurrent use.
+type Extractors struct {
+	mu         sync.RWMutex
+	extractors []Extractor
+}
+
+func NewExtractors(extractors ...Extractor) *Extractors {
+	return &Extractors{
+		extractors: slices.Clone(extractors),
+	}
+}
+
+func (e *Extractors) Register(extractor Extractor) *Extractors {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.extractors = append(e.extractors, extractor)
+
+	return e
+}
+
+func (e *Extractors) list() []Extractor {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.extractors
+}
+
+// Handler returns a handler that adds the attributes of the registered
+// extractors to records handled by next. Extractors are called in Handle
+// with the record context, so they are not called for disabled records, and
+// their attributes are qualified by the groups of the handler.
+func (e *Extractors) Handler(next slog.Handler) slog.Handler {
+	return extractorHandler{
+		next:       next,
+		extractors: e,
+	}
+}
+
+type extractorHandler struct {
+	next       slog.Handler
+	extractors *Extractors
+}
+
+func (h extractorHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// RecordDisabled forwards disabled records to next, see DisabledRecorder.
+func (h extractorHandler) RecordDisabled(ctx context.Context, level slog.Level) {
+	recordDisabled(ctx, h.next, level)
+}
+
+// EnabledByAttrs reports whether next enables levels by attributes, see
+// AttrsEnabler.
+func (h extractorHandler) EnabledByAttrs() bool {
+	return enabledByAttrs(h.next)
+}
+
+func (h extractorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h.next = h.next.WithAttrs(attrs)
+
+	return h
+}
+
+func (h extractorHandler) WithGroup(name string) slog.Handler {
+	h.next = h.next.WithGroup(name)
+
+	return h
+}
+
+func (h extractorHandler) Handle(ctx context.Context, record slog.Record) error {
+	cloned := false
+
+	for _, extractor := range h.extractors.list() {
+		attrs := extractor(ctx)
+		if len(attrs) == 0 {
+			continue
+		}
+
+		if !cloned {
+			record = record.Clone()
+			cloned = true
+		}
+
+		record.AddAttrs(attrs...)
+	}
+
+	return h.next.Handle(ctx, record)
+}
@@ -0,0 +1,146 @@
+package alog_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+type tenantKey struct{}
+
+func tenantExtractor(ctx context.Context) []slog.Attr {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok {
+		return nil
+	}
+
+	return []slog.Attr{slog.String("tenant", tenant)}
+}
+
+func Test_Extractors_Handler(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("without tenant", "a", 1),
+		alogtest.Info("with tenant", "a", 1, "tenant", "acme", "user", 100),
+		alogtest.Info("in group", "a", 1, slog.Group("g", "b", 2, "tenant", "acme", "user", 100)),
+	)
+
+	extractors := alog.NewExtractors(tenantExtractor).
+		Register(func(ctx context.Context) []slog.Attr {
+			if ctx.Value(tenantKey{}) == nil {
+				return nil
+			}
+
+			return []slog.Attr{slog.Int("user", 100)}
+		})
+
+	ctx := alog.Context(t.Context(), extractors.Handler(handler))
+	ctx = alog.With(ctx, "a", 1)
+
+	alog.Info(ctx, "without tenant")
+
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+
+	alog.Info(ctx, "with tenant")
+
+	ctx = alog.WithGroup(ctx, "g")
+
+	alog.Info(ctx, "in group", "b", 2)
+}
+
+func Test_Extractors_Disabled(t *testing.T) {
+	calls := atomic.Int64{}
+
+	extractors := alog.NewExtractors(func(context.Context) []slog.Attr {
+		calls.Add(1)
+
+		return nil
+	})
+
+	h := extractors.Handler(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	ctx := alog.Context(t.Context(), h)
+	ctx = alog.With(ctx, "a", 1)
+
+	alog.Debug(ctx, "debug")
+
+	if calls.Load() != 0 {
+		t.Fatalf("extractor called for disabled record")
+	}
+
+	alog.Info(ctx, "info")
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 extractor call, actual %d", calls.Load())
+	}
+}
+
+func Test_Extractors_DisabledRecords(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		&alogtest.AssertOptions{
+			Level: slog.LevelInfo,
+		},
+		alogtest.Debug("debug"),
+		alogtest.Info("info"),
+	)
+
+	ctx := alog.Context(t.Context(), alog.NewExtractors().Handler(handler))
+
+	alog.Debug(ctx, "debug")
+	alog.Info(ctx, "info")
+}
+
+func Test_Extractors_EnabledByAttrs(t *testing.T) {
+	out := new(strings.Builder)
+
+	h := alog.NewExtractors().Handler(verboseHandler{Handler: slog.NewTextHandler(out, nil)})
+
+	ctx := alog.Context(t.Context(), h)
+	ctx = alog.With(ctx, alog.OpKey, "verbose")
+
+	alog.Debug(ctx, "verbose")
+
+	if !strings.Contains(out.String(), "msg=verbose op=verbose") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func Test_Extractors_Concurrent(t *testing.T) {
+	const concurrency = 100
+
+	extractors := &alog.Extractors{}
+
+	ctx := alog.Context(t.Context(), extractors.Handler(slog.NewTextHandler(io.Discard, nil)))
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+
+	wg := sync.WaitGroup{}
+
+	wg.Add(2 * concurrency)
+
+	for range concurrency {
+		go func() {
+			defer wg.Done()
+
+			extractors.Register(tenantExtractor)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			alog.Info(ctx, "msg")
+		}()
+	}
+
+	wg.Wait()
+}
+
+func Test_Extractors_HandlerConformance(t *testing.T) {
+	alogtest.RunHandlerTests(t, alog.NewExtractors(tenantExtractor).Handler)
+}
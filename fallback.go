@@ -0,0 +1,121 @@
+package alog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+const (
+	FallbackKey       = "alog_fallback"
+	FallbackCallerKey = "alog_fallback_caller"
+)
+
+// FallbackOptions configure logging with contexts without a handler.
+type FallbackOptions struct {
+	// Handler is used for contexts without a handler, slog.Default().Handler() if nil.
+	Handler slog.Handler
+	// Tag adds alog_fallback=true and the record call site to fallback records.
+	Tag bool
+	// Report is called with the current stack on every fallback hit.
+	Report func(stack []byte)
+}
+
+var (
+	fallbackOptions atomic.Pointer[FallbackOptions]
+	fallbackHits    atomic.Int64
+)
+
+// SetFallback sets the fallback options for the process, nil restores the
+// default fallback to slog.Default().Handler(). The returned function restores
+// the previous options.
+func SetFallback(opts *FallbackOptions) (restore func()) {
+	prev := fallbackOptions.Swap(opts)
+
+	return func() {
+		fallbackOptions.Store(prev)
+	}
+}
+
+// FallbackHits returns the number of records logged and handlers returned
+// for contexts without a handler.
+func FallbackHits() int64 {
+	return fallbackHits.Load()
+}
+
+// fallbackHandler returns the handler for contexts without a handler.
+func fallbackHandler() slog.Handler {
+	opts := fallbackOptions.Load()
+
+	h := slog.Default().Handler()
+	if opts != nil && opts.Handler != nil {
+		h = opts.Handler
+	}
+
+	if opts != nil && opts.Tag {
+		h = fallbackTagHandler{next: h}
+	}
+
+	return h
+}
+
+// fallbackHit counts and reports the use of a context without a handler.
+func fallbackHit() {
+	fallbackHits.Add(1)
+
+	opts := fallbackOptions.Load()
+	if opts != nil && opts.Report != nil {
+		opts.Report(debug.Stack())
+	}
+}
+
+type fallbackTagHandler struct {
+	next slog.Handler
+}
+
+func (h fallbackTagHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// RecordDisabled forwards disabled records to next, see DisabledRecorder.
+func (h fallbackTagHandler) RecordDisabled(ctx context.Context, level slog.Level) {
+	recordDisabled(ctx, h.next, level)
+}
+
+// EnabledByAttrs reports whether next enables levels by attributes, see
+// AttrsEnabler.
+func (h fallbackTagHandler) EnabledByAttrs() bool {
+	return enabledByAttrs(h.next)
+}
+
+func (h fallbackTagHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h.next = h.next.WithAttrs(attrs)
+
+	return h
+}
+
+func (h fallbackTagHandler) WithGroup(name string) slog.Handler {
+	h.next = h.next.WithGroup(name)
+
+	return h
+}
+
+func (h fallbackTagHandler) Handle(ctx context.Context, record slog.Record) error {
+	record = record.Clone()
+
+	record.AddAttrs(
+		slog.Bool(FallbackKey, true),
+		slog.String(FallbackCallerKey, pcCaller(record.PC)),
+	)
+
+	return h.next.Handle(ctx, record)
+}
+
+func pcCaller(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+}
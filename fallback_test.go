@@ -0,0 +1,111 @@
+package alog_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/amidgo/alog"
+)
+
+func Test_Fallback_Handler(t *testing.T) {
+	h := newCaptureHandler()
+
+	t.Cleanup(alog.SetFallback(&alog.FallbackOptions{Handler: h, Tag: true}))
+
+	ctx := context.Background()
+
+	_, file, line, _ := runtime.Caller(0)
+	alog.Info(ctx, "lost")
+	alog.Info(alog.With(ctx, "a", 1), "lost with")
+	alog.Channel(ctx, "audit").Info("lost channel")
+
+	records := h.Records()
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, actual %d", len(records))
+	}
+
+	for i, rec := range records {
+		if !rec.attrs[alog.FallbackKey].Bool() {
+			t.Fatalf("record %d is not tagged", i)
+		}
+
+		expectedCaller := fmt.Sprintf("%s:%d", file, line+i+1)
+
+		if rec.attrs[alog.FallbackCallerKey].String() != expectedCaller {
+			t.Fatalf("unexpected caller %s, expected %s", rec.attrs[alog.FallbackCallerKey], expectedCaller)
+		}
+	}
+
+	if records[1].attrs["a"].Int64() != 1 {
+		t.Fatal("context attributes are not logged to fallback handler")
+	}
+}
+
+func Test_Fallback_Hits(t *testing.T) {
+	h := newCaptureHandler()
+
+	t.Cleanup(alog.SetFallback(&alog.FallbackOptions{Handler: h}))
+
+	hits := alog.FallbackHits()
+
+	ctx := alog.With(context.Background(), "a", 1)
+
+	alog.Debug(context.Background(), "lost")
+	alog.Info(ctx, "lost with")
+	_ = alog.Handler(ctx)
+	_ = alog.Channel(context.Background(), "audit").Handler()
+
+	if alog.FallbackHits()-hits != 4 {
+		t.Fatalf("expected 4 fallback hits, actual %d", alog.FallbackHits()-hits)
+	}
+
+	hits = alog.FallbackHits()
+
+	ctx = alog.Context(ctx, h)
+
+	alog.Info(ctx, "with handler")
+	alog.Info(alog.With(ctx, "b", 2), "with handler")
+
+	if alog.FallbackHits() != hits {
+		t.Fatalf("unexpected fallback hits for context with handler")
+	}
+}
+
+func Test_Fallback_Report(t *testing.T) {
+	var stacks []string
+
+	t.Cleanup(alog.SetFallback(
+		&alog.FallbackOptions{
+			Handler: slog.DiscardHandler,
+			Report: func(stack []byte) {
+				stacks = append(stacks, string(stack))
+			},
+		},
+	))
+
+	lostLogger(context.Background())
+
+	if len(stacks) != 1 || !strings.Contains(stacks[0], "alog_test.lostLogger") {
+		t.Fatalf("unexpected reported stacks %v", stacks)
+	}
+}
+
+func lostLogger(ctx context.Context) {
+	alog.Info(ctx, "lost")
+}
+
+func Test_SetFallback_Restore(t *testing.T) {
+	h := newCaptureHandler()
+
+	restore := alog.SetFallback(&alog.FallbackOptions{Handler: h})
+
+	restore()
+
+	if alog.Handler(context.Background()) != slog.Default().Handler() {
+		t.Fatal("fallback is not restored")
+	}
+}
@@ -0,0 +1,134 @@
+package alog
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+const (
+	IndexKey  = "index"
+	TasksKey  = "tasks"
+	FailedKey = "failed"
+)
+
+// Group runs tasks in goroutines as child operations of the group operation,
+// the group context is canceled with the first task error as the cause.
+type Group struct {
+	op     Operation
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	tasks int
+	errs  []taskError
+}
+
+type taskError struct {
+	index int
+	err   error
+}
+
+func NewGroup(ctx context.Context, opName string, additionalArgs ...any) *Group {
+	op := startPC(ctx, callerPC(1), opName, nil, additionalArgs)
+
+	groupCtx, cancel := context.WithCancelCause(op.Context())
+
+	return &Group{
+		op:     op,
+		ctx:    groupCtx,
+		cancel: cancel,
+	}
+}
+
+// Context returns the group context, which is canceled when a task fails
+// or Wait returns.
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go runs fn in a new goroutine as a child operation with the task name and index,
+// a panic in fn is logged and reported as a *PanicError task error.
+func (g *Group) Go(name string, fn func(ctx context.Context) error) {
+	pc := callerPC(1)
+
+	g.mu.Lock()
+	index := g.tasks
+	g.tasks++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		op := startPC(g.ctx, pc, name, nil, nil, slog.Int(IndexKey, index))
+
+		err := runTask(op.Context(), fn)
+		if err != nil {
+			g.fail(index, op.errorPC(pc, err, nil))
+
+			return
+		}
+
+		op.finishPC(pc, nil)
+	}()
+}
+
+func runTask(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer RecoverError(ctx, &err)
+
+	return fn(ctx)
+}
+
+func (g *Group) fail(index int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.errs) == 0 {
+		g.cancel(err)
+	} else if errors.Is(err, context.Canceled) {
+		// the task reports the group cancellation caused by the first error
+		return
+	}
+
+	g.errs = append(g.errs, taskError{index: index, err: err})
+}
+
+// Wait waits for all tasks and finishes the group operation, it returns
+// the task errors joined in the order the tasks were started. Cancellation
+// errors of tasks that fail after another task are left out, they only
+// report the group cancellation. The group failure is logged under every
+// LoggedPolicy, with already logged task errors referenced by record ID.
+func (g *Group) Wait() error {
+	pc := callerPC(1)
+
+	g.wg.Wait()
+	g.cancel(nil)
+
+	g.mu.Lock()
+	tasks, errs := g.tasks, slices.Clone(g.errs)
+	g.mu.Unlock()
+
+	if len(errs) == 0 {
+		g.op.finishPC(pc, nil, slog.Int(TasksKey, tasks))
+
+		return nil
+	}
+
+	slices.SortFunc(errs, func(a, b taskError) int {
+		return cmp.Compare(a.index, b.index)
+	})
+
+	joined := make([]error, 0, len(errs))
+
+	for _, taskErr := range errs {
+		joined = append(joined, taskErr.err)
+	}
+
+	return g.op.outcomePC(pc, errors.Join(joined...), nil, slog.Int(TasksKey, tasks), slog.Int(FailedKey, len(errs)))
+}
@@ -0,0 +1,220 @@
+package alog_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func Test_Group(t *testing.T) {
+	group := alogtest.NewOp("fetch", "user_id", 1)
+
+	handler := alogtest.NewHandler(t,
+		&alogtest.AssertOptions{CheckOrder: false},
+		group.Start(),
+		group.Op("profile", alog.IndexKey, 0).Start(),
+		group.Op("profile", alog.IndexKey, 0).Info("loaded"),
+		group.Op("profile", alog.IndexKey, 0).Finish(),
+		group.Op("orders", alog.IndexKey, 1).Start(),
+		group.Op("orders", alog.IndexKey, 1).Finish(),
+		group.Finish(alog.TasksKey, 2),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	g := alog.NewGroup(ctx, "fetch", "user_id", 1)
+
+	g.Go("profile", func(ctx context.Context) error {
+		alog.Info(ctx, "loaded")
+
+		return nil
+	})
+
+	g.Go("orders", func(context.Context) error {
+		return nil
+	})
+
+	err := g.Wait()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if g.Context().Err() == nil {
+		t.Fatal("group context is not canceled after Wait")
+	}
+}
+
+func Test_Group_Errors(t *testing.T) {
+	group := alogtest.NewOp("fetch")
+
+	handler := alogtest.NewHandler(t,
+		&alogtest.AssertOptions{CheckOrder: false},
+		group.Start(),
+		group.Op("first", alog.IndexKey, 0).Start(),
+		group.Op("first", alog.IndexKey, 0).Error(io.ErrUnexpectedEOF),
+		group.Op("waiter", alog.IndexKey, 1).Start(),
+		group.Op("waiter", alog.IndexKey, 1).Finish(),
+		group.Op("second", alog.IndexKey, 2).Start(),
+		group.Op("second", alog.IndexKey, 2).Error(errNotFound),
+		group.Error(errors.Join(io.ErrUnexpectedEOF, errNotFound), alog.TasksKey, 3, alog.FailedKey, 2),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	g := alog.NewGroup(ctx, "fetch")
+
+	g.Go("first", func(context.Context) error {
+		return io.ErrUnexpectedEOF
+	})
+
+	g.Go("waiter", func(ctx context.Context) error {
+		<-ctx.Done()
+
+		if !errors.Is(context.Cause(ctx), io.ErrUnexpectedEOF) {
+			t.Errorf("unexpected cause: %v", context.Cause(ctx))
+		}
+
+		return nil
+	})
+
+	<-g.Context().Done()
+
+	g.Go("second", func(context.Context) error {
+		return errNotFound
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, io.ErrUnexpectedEOF) || !errors.Is(err, errNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func Test_Group_CanceledTasks(t *testing.T) {
+	h := newCaptureHandler()
+
+	ctx := alog.Context(t.Context(), h)
+
+	g := alog.NewGroup(ctx, "fetch")
+
+	g.Go("first", func(context.Context) error {
+		return io.ErrUnexpectedEOF
+	})
+
+	g.Go("waiter", func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records := h.Records()
+
+	for _, rec := range records[:len(records)-1] {
+		if rec.attrs[alog.OpKey].String() != "waiter" || rec.record.Message != alog.ErrorMsg {
+			continue
+		}
+
+		if rec.record.Level != slog.LevelWarn || rec.attrs[alog.OutcomeKey].String() != alog.OutcomeCanceled {
+			t.Fatalf("unexpected waiter record %s %s", rec.record.Level, rec.attrs[alog.OutcomeKey])
+		}
+	}
+
+	groupErr := records[len(records)-1]
+
+	if groupErr.record.Level != slog.LevelError || groupErr.record.Message != alog.ErrorMsg {
+		t.Fatalf("unexpected group record %s %s", groupErr.record.Level, groupErr.record.Message)
+	}
+
+	if _, ok := groupErr.attrs[alog.OutcomeKey]; ok {
+		t.Fatalf("unexpected group outcome %s", groupErr.attrs[alog.OutcomeKey])
+	}
+
+	if groupErr.attrs[alog.ErrorKey].String() != io.ErrUnexpectedEOF.Error() || groupErr.attrs[alog.FailedKey].Int64() != 1 {
+		t.Fatalf("unexpected group error %s, failed %s", groupErr.attrs[alog.ErrorKey], groupErr.attrs[alog.FailedKey])
+	}
+}
+
+func Test_Group_LoggedPolicy(t *testing.T) {
+	sequentialRecordIDs(t)
+
+	group := alogtest.NewOp("fetch")
+
+	handler := alogtest.NewHandler(t,
+		&alogtest.AssertOptions{CheckOrder: false},
+		group.Start(),
+		group.Op("first", alog.IndexKey, 0).Start(),
+		group.Op("first", alog.IndexKey, 0).Error(io.ErrUnexpectedEOF, alog.RecordIDKey, "id1"),
+		group.Log(slog.LevelError, alog.ErrorMsg, alog.LoggedKey, "id1", alog.TasksKey, 1, alog.FailedKey, 1),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.WithLoggedPolicy(ctx, alog.LoggedSkip)
+
+	g := alog.NewGroup(ctx, "fetch")
+
+	g.Go("first", func(context.Context) error {
+		return io.ErrUnexpectedEOF
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func Test_Group_Panic(t *testing.T) {
+	h := newCaptureHandler()
+
+	ctx := alog.Context(t.Context(), h)
+
+	g := alog.NewGroup(ctx, "fetch")
+
+	g.Go("panics", func(context.Context) error {
+		panic("boom")
+	})
+
+	err := g.Wait()
+
+	var panicErr *alog.PanicError
+
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records := h.Records()
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records, actual %d", len(records))
+	}
+
+	assertPanicRecord(t, records[2], "boom", "string", "alog_test.Test_Group_Panic.func1")
+
+	taskErr := records[3]
+
+	if taskErr.record.Level != slog.LevelError || taskErr.record.Message != alog.ErrorMsg {
+		t.Fatalf("unexpected task record %s %s", taskErr.record.Level, taskErr.record.Message)
+	}
+
+	if taskErr.attrs[alog.ErrorKey].String() != "panic: boom" {
+		t.Fatalf("unexpected task error %s", taskErr.attrs[alog.ErrorKey])
+	}
+}
+
+func Test_Group_CallerSource(t *testing.T) {
+	alogtest.AssertCallerSource(t, func(ctx context.Context) {
+		g := alog.NewGroup(ctx, "group")
+
+		g.Go("ok", func(context.Context) error { return nil })
+		g.Go("fail", func(context.Context) error { return io.EOF })
+
+		_ = g.Wait()
+	})
+}
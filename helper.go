@@ -0,0 +1,131 @@
+package alog
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	maxHelperDepth = 32
+	// helperBatch is the number of frames read at once above a helper.
+	helperBatch = 8
+)
+
+// helpers holds the functions marked by Helper. The maps read by logging
+// calls are immutable, they are replaced under mu when a new entry is added,
+// so logging calls read them without locks.
+var helpers = struct {
+	mu sync.Mutex
+	// names are the names of functions marked by Helper.
+	names map[string]struct{}
+	// callSites are the PCs of Helper calls already registered.
+	callSites atomic.Pointer[map[uintptr]struct{}]
+	// pcs caches whether the innermost function of a PC is a helper,
+	// it is cleared when a new helper is marked.
+	pcs atomic.Pointer[map[uintptr]bool]
+}{
+	names: make(map[string]struct{}),
+}
+
+var helpersMarked atomic.Bool
+
+// Helper marks the calling function as a logging helper, records logged
+// by alog functions called from it report the source of its caller.
+// Like testing.T.Helper it should be called at the start of the helper.
+func Helper() {
+	pcs := [1]uintptr{}
+	runtime.Callers(2, pcs[:])
+
+	if callSites := helpers.callSites.Load(); callSites != nil {
+		if _, ok := (*callSites)[pcs[0]]; ok {
+			return
+		}
+	}
+
+	markHelper(pcs[0])
+}
+
+// markHelper registers the Helper call site pc and the name of its function.
+func markHelper(pc uintptr) {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	helpers.mu.Lock()
+	defer helpers.mu.Unlock()
+
+	callSites := map[uintptr]struct{}{pc: {}}
+	if prev := helpers.callSites.Load(); prev != nil {
+		for pc := range *prev {
+			callSites[pc] = struct{}{}
+		}
+	}
+
+	helpers.callSites.Store(&callSites)
+	helpers.names[frame.Function] = struct{}{}
+	helpers.pcs.Store(nil)
+
+	helpersMarked.Store(true)
+}
+
+// callerPC returns the PC of the caller skip frames above the caller of
+// callerPC, skipping functions marked by Helper. Only the caller is read
+// unless it is a helper.
+func callerPC(skip int) uintptr {
+	pcs := [1]uintptr{}
+	runtime.Callers(skip+2, pcs[:])
+
+	if !helpersMarked.Load() || !isHelperPC(pcs[0]) {
+		return pcs[0]
+	}
+
+	return helperCallerPC(skip+4, pcs[0])
+}
+
+// helperCallerPC returns the first PC that is not a helper, reading the
+// frames in batches from the frame skip frames above runtime.Callers. pc is
+// returned if every frame up to maxHelperDepth is a helper.
+func helperCallerPC(skip int, pc uintptr) uintptr {
+	for read := 0; read < maxHelperDepth; read += helperBatch {
+		pcs := [helperBatch]uintptr{}
+		n := runtime.Callers(skip+read, pcs[:])
+
+		for _, callerPC := range pcs[:n] {
+			if !isHelperPC(callerPC) {
+				return callerPC
+			}
+		}
+
+		if n < len(pcs) {
+			break
+		}
+	}
+
+	return pc
+}
+
+func isHelperPC(pc uintptr) bool {
+	cache := helpers.pcs.Load()
+	if cache != nil {
+		if helper, ok := (*cache)[pc]; ok {
+			return helper
+		}
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	helpers.mu.Lock()
+	defer helpers.mu.Unlock()
+
+	_, helper := helpers.names[frame.Function]
+
+	pcs := map[uintptr]bool{pc: helper}
+	if prev := helpers.pcs.Load(); prev != nil {
+		for cached, isHelper := range *prev {
+			pcs[cached] = isHelper
+		}
+	}
+
+	helpers.pcs.Store(&pcs)
+
+	return helper
+}
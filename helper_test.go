@@ -0,0 +1,112 @@
+package alog_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func logDepthHelper(ctx context.Context) {
+	alog.LogDepth(ctx, 1, slog.LevelInfo, "depth", "a", 1)
+	alog.LogAttrsDepth(ctx, 1, slog.LevelInfo, "depth attrs", slog.Int("a", 1))
+}
+
+func Test_LogDepth(t *testing.T) {
+	alogtest.AssertCallerSource(t, func(ctx context.Context) {
+		alog.LogDepth(ctx, 0, slog.LevelInfo, "depth")
+		alog.LogAttrsDepth(ctx, 0, slog.LevelInfo, "depth attrs")
+		logDepthHelper(ctx)
+	})
+}
+
+func infoHelper(ctx context.Context, msg string) {
+	alog.Helper()
+
+	alog.Info(ctx, msg)
+}
+
+func nestedHelper(ctx context.Context) {
+	alog.Helper()
+
+	infoHelper(ctx, "nested")
+	alog.Channel(ctx, "audit").Warn("channel")
+}
+
+func startHelper(ctx context.Context) alog.Operation {
+	alog.Helper()
+
+	return alog.Start(ctx, "op")
+}
+
+func Test_Helper(t *testing.T) {
+	alogtest.AssertCallerSource(t, func(ctx context.Context) {
+		infoHelper(ctx, "helper")
+		nestedHelper(ctx)
+
+		op := startHelper(ctx)
+		op.Finish()
+	})
+}
+
+func deepHelper(ctx context.Context, depth int) {
+	alog.Helper()
+
+	if depth > 0 {
+		deepHelper(ctx, depth-1)
+
+		return
+	}
+
+	alog.Info(ctx, "deep")
+}
+
+func Test_Helper_Deep(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("deep").At(alogtest.Source{Marker: "deepHelper(ctx, 10)"}),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	deepHelper(ctx, 10)
+}
+
+func Test_Helper_Source(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("helper").At(alogtest.Source{Marker: `infoHelper(ctx, "helper")`}),
+		alogtest.Info("helper").At(alogtest.Source{Marker: `infoHelper(ctx, "helper") // second`}),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	infoHelper(ctx, "helper")
+	infoHelper(ctx, "helper") // second
+}
+
+func BenchmarkHelper(b *testing.B) {
+	ctx := alog.Context(b.Context(), slog.NewTextHandler(io.Discard, nil))
+
+	// direct calls are measured with a helper marked
+	infoHelper(ctx, "msg")
+
+	b.Run("direct", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			alog.Info(ctx, "msg")
+		}
+	})
+
+	b.Run("helper", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			infoHelper(ctx, "msg")
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package alog
+
+import "log/slog"
+
+// LazyValue is a value computed only when a record with it is handled,
+// so it costs nothing for disabled levels.
+type LazyValue[T any] func() T
+
+// Lazy returns a value computed by f only when the record is handled.
+// f must not capture variables to keep logging with disabled levels free of
+// allocations, a capturing closure is allocated before the level is checked.
+func Lazy[T any](f func() T) LazyValue[T] {
+	return f
+}
+
+func (v LazyValue[T]) LogValue() slog.Value {
+	if v == nil {
+		return slog.AnyValue(nil)
+	}
+
+	return slog.AnyValue(v())
+}
@@ -0,0 +1,71 @@
+package alog_test
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+var diffCalls int
+
+func diff() string {
+	diffCalls++
+
+	return "-a +b"
+}
+
+func body() any {
+	return map[string]int{"id": 1}
+}
+
+func Test_Lazy(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("changed", "diff", "-a +b", "body", map[string]int{"id": 1}),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	alog.Info(ctx, "changed", "diff", alog.Lazy(diff), "body", body)
+}
+
+func Test_Lazy_Disabled(t *testing.T) {
+	out := new(strings.Builder)
+
+	ctx := alog.Context(t.Context(), slog.NewTextHandler(out, nil))
+
+	diffCalls = 0
+
+	allocs := testing.AllocsPerRun(100, func() {
+		alog.Debug(ctx, "changed", "diff", alog.Lazy(diff), "body", body)
+	})
+
+	if allocs != 0 {
+		t.Fatalf("disabled debug with lazy args allocates %v times", allocs)
+	}
+
+	if diffCalls != 0 {
+		t.Fatalf("lazy value of disabled record computed %d times", diffCalls)
+	}
+
+	alog.Info(ctx, "changed", "diff", alog.Lazy(diff), "body", body)
+
+	if diffCalls != 1 {
+		t.Fatalf("lazy value of enabled record computed %d times", diffCalls)
+	}
+
+	if !strings.Contains(out.String(), `diff="-a +b" body=map[id:1]`) {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func Test_Lazy_Nil(t *testing.T) {
+	var v alog.LazyValue[string]
+
+	if !v.LogValue().Equal(slog.AnyValue(nil)) {
+		t.Fatalf("unexpected nil lazy value %s", v.LogValue())
+	}
+}
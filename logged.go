@@ -0,0 +1,211 @@
+package alog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"slices"
+)
+
+const (
+	RecordIDKey = "record_id"
+	LoggedKey   = "logged_as"
+)
+
+// LoggedPolicy decides how errors that have already been logged by Err or
+// Operation.Err are logged again.
+type LoggedPolicy int
+
+const (
+	// LoggedLog logs errors as usual and does not mark them, it is the default.
+	LoggedLog LoggedPolicy = iota
+	// LoggedSkip drops records with already logged errors.
+	LoggedSkip
+	// LoggedDebug logs records with already logged errors at Debug.
+	LoggedDebug
+	// LoggedReference replaces already logged errors with the record ID of
+	// the first record.
+	LoggedReference
+)
+
+type loggedPolicyKey struct{}
+
+// WithLoggedPolicy makes Err, Operation.Error and Operation.Err add a record ID
+// to records, Err and Operation.Err return errors marked as logged, which are
+// then logged again by the policy.
+func WithLoggedPolicy(ctx context.Context, policy LoggedPolicy) context.Context {
+	return context.WithValue(ctx, loggedPolicyKey{}, policy)
+}
+
+func loggedPolicyFromContext(ctx context.Context) LoggedPolicy {
+	policy, _ := ctx.Value(loggedPolicyKey{}).(LoggedPolicy)
+
+	return policy
+}
+
+type loggedError struct {
+	err      error
+	recordID string
+}
+
+func (e *loggedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *loggedError) Unwrap() error {
+	return e.err
+}
+
+func MarkLogged(err error, recordID string) error {
+	if err == nil {
+		return nil
+	}
+
+	return &loggedError{
+		err:      err,
+		recordID: recordID,
+	}
+}
+
+func LoggedRecordID(err error) (string, bool) {
+	var logged *loggedError
+
+	if errors.As(err, &logged) {
+		return logged.recordID, true
+	}
+
+	return "", false
+}
+
+var newRecordID = randomRecordID
+
+func randomRecordID() string {
+	id := [8]byte{}
+
+	_, _ = rand.Read(id[:])
+
+	return hex.EncodeToString(id[:])
+}
+
+type loggedRecord struct {
+	level slog.Level
+	// attrs are stored inline to keep logging errors free of allocations.
+	attrs  [2]slog.Attr
+	nAttrs int
+	err    error
+	skip   bool
+}
+
+// outcomePolicy returns the policy for the outcome record of an operation
+// failed by errors of its child operations, already logged errors are
+// referenced instead of being skipped or logged at Debug, so the outcome
+// is always logged.
+func outcomePolicy(policy LoggedPolicy) LoggedPolicy {
+	if policy == LoggedLog {
+		return LoggedLog
+	}
+
+	return LoggedReference
+}
+
+func newLoggedRecord(policy LoggedPolicy, err error, level slog.Level) loggedRecord {
+	rec := loggedRecord{
+		level:  level,
+		attrs:  [2]slog.Attr{ErrorAttr(err)},
+		nAttrs: 1,
+		err:    err,
+	}
+
+	if policy == LoggedLog || err == nil {
+		return rec
+	}
+
+	recordID, logged := LoggedRecordID(err)
+	if !logged {
+		recordID = newRecordID()
+
+		rec.attrs[1] = slog.String(RecordIDKey, recordID)
+		rec.nAttrs = 2
+		rec.err = MarkLogged(err, recordID)
+
+		return rec
+	}
+
+	switch policy {
+	case LoggedSkip:
+		rec.skip = true
+	case LoggedDebug:
+		rec.level = slog.LevelDebug
+	case LoggedReference:
+		rec.attrs[0] = slog.String(LoggedKey, recordID)
+	}
+
+	return rec
+}
+
+func (rec *loggedRecord) appendAttrs(attrs []slog.Attr) []slog.Attr {
+	return append(attrs, rec.attrs[:rec.nAttrs]...)
+}
+
+func applyLoggedPolicy(ctx context.Context, level slog.Level, args []any) (slog.Level, []any, bool) {
+	policy := loggedPolicyFromContext(ctx)
+	if policy == LoggedLog {
+		return level, args, false
+	}
+
+	var referenced []any
+
+	for i := 0; i < len(args); {
+		n := argLen(args[i:])
+
+		recordID, logged := LoggedRecordID(argError(args[i : i+n]))
+
+		switch {
+		case !logged:
+			if referenced != nil {
+				referenced = append(referenced, args[i:i+n]...)
+			}
+		case policy == LoggedSkip:
+			return level, args, true
+		case policy == LoggedDebug:
+			level = slog.LevelDebug
+		case policy == LoggedReference:
+			if referenced == nil {
+				referenced = slices.Clip(args[:i])
+			}
+
+			referenced = append(referenced, slog.String(LoggedKey, recordID))
+		}
+
+		i += n
+	}
+
+	if referenced != nil {
+		return level, referenced, false
+	}
+
+	return level, args, false
+}
+
+func argLen(args []any) int {
+	if _, ok := args[0].(string); ok && len(args) > 1 {
+		return 2
+	}
+
+	return 1
+}
+
+func argError(arg []any) error {
+	switch x := arg[len(arg)-1].(type) {
+	case error:
+		return x
+	case slog.Attr:
+		err, _ := x.Value.Any().(error)
+
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,151 @@
+package alog_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func sequentialRecordIDs(t *testing.T) {
+	id := 0
+
+	restore := alog.SetNewRecordID(func() string {
+		id++
+
+		return "id" + strconv.Itoa(id)
+	})
+
+	t.Cleanup(restore)
+}
+
+func Test_LoggedPolicy_Reference(t *testing.T) {
+	sequentialRecordIDs(t)
+
+	op := alogtest.NewOp("request")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Error("query failed", alog.ErrorKey, io.EOF.Error(), alog.RecordIDKey, "id1", "table", "users"),
+		alogtest.Error("service failed", alog.LoggedKey, "id1"),
+		alogtest.Error("request failed", "path", "/", alog.LoggedKey, "id1"),
+		alogtest.Info("retry", alog.LoggedKey, "id1", "attempt", 1),
+		alogtest.Error("other", alog.ErrorKey, io.ErrUnexpectedEOF.Error()),
+		op.Start(),
+		op.Log(slog.LevelError, alog.ErrorMsg, alog.LoggedKey, "id1").At(alogtest.Source{Marker: "aop.Error(err)"}),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.WithLoggedPolicy(ctx, alog.LoggedReference)
+
+	err := alog.Err(ctx, io.EOF, "query failed", "table", "users")
+
+	if !errors.Is(err, io.EOF) || err.Error() != io.EOF.Error() {
+		t.Fatalf("unexpected marked error %v", err)
+	}
+
+	recordID, ok := alog.LoggedRecordID(err)
+	if !ok || recordID != "id1" {
+		t.Fatalf("unexpected record id %q %t", recordID, ok)
+	}
+
+	err = fmt.Errorf("service: %w", err)
+
+	returned := alog.Err(ctx, err, "service failed")
+	if returned != err {
+		t.Fatal("already logged error has been wrapped again")
+	}
+
+	alog.Error(ctx, "request failed", "path", "/", err)
+	alog.Info(ctx, "retry", "cause", err, "attempt", 1)
+	alog.Error(ctx, "other", io.ErrUnexpectedEOF)
+
+	aop := alog.Start(ctx, "request")
+	aop.Error(err)
+}
+
+func Test_LoggedPolicy_Skip(t *testing.T) {
+	sequentialRecordIDs(t)
+
+	op := alogtest.NewOp("request")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Error(io.EOF, alog.RecordIDKey, "id1"),
+		op.Start(),
+		alogtest.Info("done"),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.WithLoggedPolicy(ctx, alog.LoggedSkip)
+
+	aop := alog.Start(ctx, "request")
+	err := aop.Err(io.EOF)
+
+	alog.Err(ctx, err, "service failed")
+	alog.Error(ctx, "request failed", err)
+	alog.Start(ctx, "request").Error(err)
+
+	alog.Info(ctx, "done")
+}
+
+func Test_LoggedPolicy_Debug(t *testing.T) {
+	sequentialRecordIDs(t)
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Error("query failed", alog.ErrorKey, io.EOF.Error(), alog.RecordIDKey, "id1"),
+		alogtest.Debug("service failed", alog.ErrorKey, io.EOF.Error()),
+		alogtest.Debug("request failed", alog.ErrorKey, io.EOF.Error()),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.WithLoggedPolicy(ctx, alog.LoggedDebug)
+
+	err := alog.Err(ctx, io.EOF, "query failed")
+
+	alog.Err(ctx, err, "service failed")
+	alog.Error(ctx, "request failed", err)
+}
+
+func Test_LoggedPolicy_Default(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Error("query failed", alog.ErrorKey, io.EOF.Error()),
+		alogtest.Error("service failed", alog.ErrorKey, io.EOF.Error()),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	err := alog.Err(ctx, io.EOF, "query failed")
+	if err != io.EOF {
+		t.Fatal("error has been marked without policy")
+	}
+
+	alog.Err(ctx, err, "service failed")
+}
+
+func Test_MarkLogged(t *testing.T) {
+	if alog.MarkLogged(nil, "id") != nil {
+		t.Fatal("nil error has been marked")
+	}
+
+	var target *validationError
+
+	err := alog.MarkLogged(&validationError{field: "name"}, "id")
+
+	if !errors.As(err, &target) || target.field != "name" {
+		t.Fatal("marked error does not preserve errors.As")
+	}
+
+	_, ok := alog.LoggedRecordID(io.EOF)
+	if ok {
+		t.Fatal("unexpected record id for not logged error")
+	}
+}
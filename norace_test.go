@@ -0,0 +1,5 @@
+//go:build !race
+
+package alog_test
+
+const raceEnabled = false
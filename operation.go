@@ -0,0 +1,389 @@
+package alog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+const OpKey = "op"
+
+const (
+	StartMsg     = "start"
+	FinishMsg    = "finish"
+	ErrorMsg     = "error"
+	HeartbeatMsg = "in progress"
+)
+
+const (
+	ElapsedKey  = "elapsed"
+	SlowKey     = "slow"
+	OutcomeKey  = "outcome"
+	CauseKey    = "cause"
+	DeadlineKey = "deadline_left"
+)
+
+const (
+	OutcomeCanceled = "canceled"
+	OutcomeTimeout  = "timeout"
+)
+
+type StartOptions struct {
+	// RollUp adds the operation counters to the parent operation
+	// counters when the operation ends.
+	RollUp bool
+	// SlowThreshold makes Finish log at Warn with slow=true when the
+	// operation takes longer.
+	SlowThreshold time.Duration
+	// HeartbeatInterval enables periodic "in progress" records until
+	// the operation ends or its context is done.
+	HeartbeatInterval time.Duration
+	Clock             Clock
+	// CanceledLevel is the level of Error records for context
+	// cancellation and deadline errors, slog.LevelWarn if nil.
+	CanceledLevel slog.Leveler
+	// Trace starts a new trace when the context has no trace to continue,
+	// records of traced operations have the trace_id and span_id attributes.
+	Trace bool
+}
+
+func startOptionsRollUp(opts *StartOptions) bool {
+	if opts != nil {
+		return opts.RollUp
+	}
+
+	return false
+}
+
+func startOptionsSlowThreshold(opts *StartOptions) time.Duration {
+	if opts != nil {
+		return opts.SlowThreshold
+	}
+
+	return 0
+}
+
+func startOptionsHeartbeatInterval(opts *StartOptions) time.Duration {
+	if opts != nil {
+		return opts.HeartbeatInterval
+	}
+
+	return 0
+}
+
+func startOptionsCanceledLevel(opts *StartOptions) slog.Leveler {
+	if opts != nil && opts.CanceledLevel != nil {
+		return opts.CanceledLevel
+	}
+
+	return slog.LevelWarn
+}
+
+func startOptionsTrace(opts *StartOptions) bool {
+	if opts != nil {
+		return opts.Trace
+	}
+
+	return false
+}
+
+func startOptionsClock(opts *StartOptions) Clock {
+	if opts != nil && opts.Clock != nil {
+		return opts.Clock
+	}
+
+	return systemClock{}
+}
+
+type Operation struct {
+	ctx           context.Context
+	event         *event
+	clock         Clock
+	start         time.Time
+	slowThreshold time.Duration
+	heartbeat     *heartbeat
+	registration  *registration
+	canceledLevel slog.Leveler
+}
+
+func Start(ctx context.Context, opName string, additionalArgs ...any) Operation {
+	return startPC(ctx, callerPC(1), opName, nil, additionalArgs)
+}
+
+// startPC starts the operation with additionalArgs followed by attrs, internal
+// callers pass attrs to avoid boxing scalar values in args.
+func startPC(ctx context.Context, pc uintptr, opName string, opts *StartOptions, additionalArgs []any, attrs ...slog.Attr) Operation {
+	op := newOperation(ctx, opName, opts, additionalArgs, attrs)
+
+	alogPC(op.ctx, pc, slog.LevelInfo, StartMsg)
+
+	return op
+}
+
+func StartWithOptions(ctx context.Context, opName string, opts *StartOptions, additionalArgs ...any) Operation {
+	pc := callerPC(1)
+
+	op := startPC(ctx, pc, opName, opts, additionalArgs)
+
+	interval := startOptionsHeartbeatInterval(opts)
+	if interval > 0 {
+		op.heartbeat = startHeartbeat(op, interval, pc)
+	}
+
+	return op
+}
+
+func newOperation(ctx context.Context, opName string, opts *StartOptions, additionalArgs []any, additionalAttrs []slog.Attr) Operation {
+	const minAttrsAmount = 3
+
+	attrs := make([]slog.Attr, 0, len(additionalArgs)+len(additionalAttrs)+minAttrsAmount)
+
+	attrs = append(attrs, slog.String(OpKey, opName))
+	attrs = appendArgs(attrs, additionalArgs)
+	attrs = append(attrs, additionalAttrs...)
+
+	trace, traced := startTrace(ctx, startOptionsTrace(opts))
+
+	ev := newEvent(eventFromContext(ctx), startOptionsRollUp(opts))
+
+	if traced {
+		ctx = withTrace(ctx, trace)
+	}
+
+	ctx = withAttrs(ctx, attrs)
+	ctx = context.WithValue(ctx, eventKey{}, ev)
+
+	clock := startOptionsClock(opts)
+
+	op := Operation{
+		ctx:           ctx,
+		event:         ev,
+		clock:         clock,
+		start:         clock.Now(),
+		slowThreshold: startOptionsSlowThreshold(opts),
+		canceledLevel: startOptionsCanceledLevel(opts),
+	}
+
+	if reg := registryFromContext(ctx); reg != nil {
+		op.registration = reg.start(opName, slices.Clip(attrs[1:]), clock, op.start)
+	}
+
+	return op
+}
+
+func (op Operation) Context() context.Context {
+	return op.ctx
+}
+
+func (op Operation) Debug(msg string, args ...any) {
+	alog(op.ctx, slog.LevelDebug, msg, args...)
+}
+
+func (op Operation) Info(msg string, args ...any) {
+	alog(op.ctx, slog.LevelInfo, msg, args...)
+}
+
+func (op Operation) Warn(msg string, args ...any) {
+	alog(op.ctx, slog.LevelWarn, msg, args...)
+}
+
+// Log logs a record with the operation attributes, use it with
+// slog.LevelError for error messages that do not fail the operation.
+func (op Operation) Log(level slog.Level, msg string, args ...any) {
+	alog(op.ctx, level, msg, args...)
+}
+
+func (op Operation) LogAttrs(level slog.Level, msg string, attrs ...slog.Attr) {
+	alogAttrs(op.ctx, level, msg, attrs...)
+}
+
+func (op Operation) Finish() {
+	op.finishPC(callerPC(1), nil)
+}
+
+// FinishWith finishes the operation like Finish and adds args to the
+// final record, before the attributes of the operation event.
+func (op Operation) FinishWith(args ...any) {
+	op.finishPC(callerPC(1), args)
+}
+
+func (op Operation) finishPC(pc uintptr, args []any, additionalAttrs ...slog.Attr) {
+	op.end(nil)
+
+	level := slog.LevelInfo
+
+	buf := getAttrs()
+	defer putAttrs(buf)
+
+	attrs := appendArgs(*buf, args)
+	attrs = append(attrs, additionalAttrs...)
+	attrs = op.event.appendAttrs(attrs)
+
+	elapsed := op.elapsed()
+	if op.slowThreshold > 0 && elapsed > op.slowThreshold {
+		level = slog.LevelWarn
+
+		attrs = append(attrs,
+			slog.Bool(SlowKey, true),
+			slog.Duration(ElapsedKey, elapsed),
+		)
+	}
+
+	alogPC(op.ctx, pc, level, FinishMsg, attrs...)
+
+	*buf = attrs
+}
+
+// Error logs the operation failure.
+func (op Operation) Error(err error, additionalArgs ...any) {
+	op.errorPC(callerPC(1), err, additionalArgs)
+}
+
+// Err logs the operation failure like Error and returns err,
+// marked as logged if the context has a LoggedPolicy.
+func (op Operation) Err(err error, additionalArgs ...any) error {
+	return op.errorPC(callerPC(1), err, additionalArgs)
+}
+
+func (op Operation) errorPC(pc uintptr, err error, additionalArgs []any, additionalAttrs ...slog.Attr) error {
+	return op.logErrorPC(pc, err, loggedPolicyFromContext(op.ctx), additionalArgs, additionalAttrs)
+}
+
+// outcomePC logs the failure of an operation with errors returned by its child
+// operations, the record is logged under every LoggedPolicy, see outcomePolicy.
+func (op Operation) outcomePC(pc uintptr, err error, additionalArgs []any, additionalAttrs ...slog.Attr) error {
+	return op.logErrorPC(pc, err, outcomePolicy(loggedPolicyFromContext(op.ctx)), additionalArgs, additionalAttrs)
+}
+
+func (op Operation) logErrorPC(
+	pc uintptr,
+	err error,
+	policy LoggedPolicy,
+	additionalArgs []any,
+	additionalAttrs []slog.Attr,
+) error {
+	if err != nil {
+		op.end(err)
+	} else {
+		op.end(errNilOperationError)
+	}
+
+	classification, _ := classify(op.ctx, err, op.canceledLevel.Level())
+
+	rec := newLoggedRecord(policy, err, classification.Level)
+	if rec.skip {
+		return rec.err
+	}
+
+	buf := getAttrs()
+	defer putAttrs(buf)
+
+	attrs := rec.appendAttrs(*buf)
+
+	if classification.Outcome != "" {
+		attrs = append(attrs, slog.String(OutcomeKey, classification.Outcome))
+	}
+
+	_, canceled := cancellationOutcome(err)
+
+	attrs = appendArgs(attrs, additionalArgs)
+	attrs = append(attrs, additionalAttrs...)
+	attrs = op.event.appendAttrs(attrs)
+
+	if canceled {
+		attrs = op.appendCancellationAttrs(attrs, err)
+	}
+
+	alogPC(op.ctx, pc, rec.level, ErrorMsg, attrs...)
+
+	*buf = attrs
+
+	return rec.err
+}
+
+func cancellationOutcome(err error) (string, bool) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return OutcomeTimeout, true
+	case errors.Is(err, context.Canceled):
+		return OutcomeCanceled, true
+	default:
+		return "", false
+	}
+}
+
+func (op Operation) appendCancellationAttrs(attrs []slog.Attr, err error) []slog.Attr {
+	attrs = append(attrs, slog.Duration(ElapsedKey, op.elapsed()))
+
+	cause := context.Cause(op.ctx)
+	if cause != nil && !errors.Is(err, cause) {
+		attrs = append(attrs, slog.String(CauseKey, cause.Error()))
+	}
+
+	deadline, ok := op.ctx.Deadline()
+	if ok {
+		attrs = append(attrs, slog.Duration(DeadlineKey, deadline.Sub(op.clock.Now())))
+	}
+
+	return attrs
+}
+
+var errNilOperationError = errors.New("nil")
+
+func (op Operation) end(err error) {
+	op.heartbeat.stop()
+	op.event.end()
+	op.registration.end(err)
+}
+
+func (op Operation) elapsed() time.Duration {
+	if op.clock == nil {
+		return 0
+	}
+
+	return op.clock.Now().Sub(op.start)
+}
+
+type heartbeat struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func startHeartbeat(op Operation, interval time.Duration, pc uintptr) *heartbeat {
+	ctx, cancel := context.WithCancel(op.ctx)
+
+	hb := &heartbeat{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(hb.done)
+
+		for {
+			timer := op.clock.NewTimer(interval)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return
+			case <-timer.C():
+				alogPC(op.ctx, pc, slog.LevelInfo, HeartbeatMsg, slog.Duration(ElapsedKey, op.elapsed()))
+			}
+		}
+	}()
+
+	return hb
+}
+
+func (hb *heartbeat) stop() {
+	if hb == nil {
+		return
+	}
+
+	hb.cancel()
+	<-hb.done
+}
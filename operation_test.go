@@ -0,0 +1,240 @@
+package alog_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func Test_Start_SlowThreshold(t *testing.T) {
+	clock := newFakeClock()
+
+	fast := alogtest.NewOp("fast")
+	slow := alogtest.NewOp("slow")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		fast.Start(),
+		fast.Finish(),
+		slow.Start(),
+		slow.FinishSlow(2*time.Second, "rows", 10),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	opts := &alog.StartOptions{
+		SlowThreshold: time.Second,
+		Clock:         clock,
+	}
+
+	fastOp := alog.StartWithOptions(ctx, "fast", opts)
+	clock.Advance(time.Second)
+	fastOp.Finish()
+
+	slowOp := alog.StartWithOptions(ctx, "slow", opts)
+	clock.Advance(2 * time.Second)
+	slowOp.FinishWith("rows", 10)
+}
+
+func Test_Start_Heartbeat(t *testing.T) {
+	clock := newFakeClock()
+
+	op := alogtest.NewOp("batch")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Heartbeat(time.Minute).At(alogtest.Source{Marker: `alog.StartWithOptions(ctx, "batch"`}),
+		op.Heartbeat(2*time.Minute),
+		op.Finish(),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	aop := alog.StartWithOptions(ctx, "batch", &alog.StartOptions{HeartbeatInterval: time.Minute, Clock: clock})
+
+	clock.WaitTimers(1)
+	clock.Advance(30 * time.Second)
+	clock.Advance(30 * time.Second)
+
+	clock.WaitTimers(2)
+	clock.Advance(time.Minute)
+
+	clock.WaitTimers(3)
+	aop.Finish()
+
+	clock.Advance(time.Minute)
+}
+
+func Test_Start_Heartbeat_Error(t *testing.T) {
+	clock := newFakeClock()
+
+	op := alogtest.NewOp("batch")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Error(io.ErrUnexpectedEOF),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	aop := alog.StartWithOptions(ctx, "batch", &alog.StartOptions{HeartbeatInterval: time.Minute, Clock: clock})
+
+	clock.WaitTimers(1)
+	aop.Error(io.ErrUnexpectedEOF)
+
+	clock.Advance(time.Minute)
+}
+
+func Test_Start_Heartbeat_ContextCanceled(t *testing.T) {
+	clock := newFakeClock()
+
+	op := alogtest.NewOp("batch")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Heartbeat(time.Minute),
+		op.Finish(),
+	)
+
+	ctx, cancel := context.WithCancel(alog.Context(t.Context(), handler))
+
+	aop := alog.StartWithOptions(ctx, "batch", &alog.StartOptions{HeartbeatInterval: time.Minute, Clock: clock})
+
+	clock.WaitTimers(1)
+	clock.Advance(time.Minute)
+	clock.WaitTimers(2)
+
+	cancel()
+
+	aop.Finish()
+}
+
+func Test_Start_HeartbeatStoppedOnCancel(t *testing.T) {
+	clock := newFakeClock()
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.NewOp("batch").Start(),
+	)
+
+	ctx, cancel := context.WithCancel(alog.Context(t.Context(), handler))
+
+	alog.StartWithOptions(ctx, "batch", &alog.StartOptions{HeartbeatInterval: time.Minute, Clock: clock})
+
+	clock.WaitTimers(1)
+
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+
+	for clock.active() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("heartbeat timer is not stopped after context cancellation")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_Operation_Error_Canceled(t *testing.T) {
+	clock := newFakeClock()
+
+	op := alogtest.NewOp("query")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.ErrorOutcome(slog.LevelWarn, context.Canceled, alog.OutcomeCanceled,
+			"table", "users",
+			alog.ElapsedKey, time.Second,
+		),
+	)
+
+	ctx, cancel := context.WithCancel(alog.Context(t.Context(), handler))
+
+	aop := alog.StartWithOptions(ctx, "query", &alog.StartOptions{Clock: clock})
+
+	clock.Advance(time.Second)
+	cancel()
+
+	aop.Error(ctx.Err(), "table", "users")
+}
+
+func Test_Operation_Error_CanceledCause(t *testing.T) {
+	clock := newFakeClock()
+
+	op := alogtest.NewOp("query")
+
+	shutdown := errors.New("server shutdown")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.ErrorOutcome(slog.LevelInfo, fmt.Errorf("query: %w", context.Canceled), alog.OutcomeCanceled,
+			alog.ElapsedKey, time.Duration(0),
+			alog.CauseKey, shutdown.Error(),
+		),
+	)
+
+	ctx, cancel := context.WithCancelCause(alog.Context(t.Context(), handler))
+
+	aop := alog.StartWithOptions(ctx, "query", &alog.StartOptions{Clock: clock, CanceledLevel: slog.LevelInfo})
+
+	cancel(shutdown)
+
+	aop.Error(fmt.Errorf("query: %w", ctx.Err()))
+}
+
+func Test_Operation_Error_Timeout(t *testing.T) {
+	clock := newFakeClock()
+
+	op := alogtest.NewOp("query")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.ErrorOutcome(slog.LevelWarn, context.DeadlineExceeded, alog.OutcomeTimeout,
+			alog.ElapsedKey, 3*time.Second,
+			alog.DeadlineKey, -time.Second,
+		),
+	)
+
+	ctx, cancel := context.WithDeadline(alog.Context(t.Context(), handler), clock.Now().Add(2*time.Second))
+	defer cancel()
+
+	aop := alog.StartWithOptions(ctx, "query", &alog.StartOptions{Clock: clock})
+
+	clock.Advance(3 * time.Second)
+
+	<-ctx.Done()
+
+	aop.Error(ctx.Err())
+}
+
+func Test_Operation_Error_NotCanceled(t *testing.T) {
+	op := alogtest.NewOp("query")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Error(io.ErrUnexpectedEOF),
+	)
+
+	ctx, cancel := context.WithCancel(alog.Context(t.Context(), handler))
+
+	aop := alog.Start(ctx, "query")
+
+	cancel()
+
+	aop.Error(io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,279 @@
+package alog_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amidgo/alog"
+)
+
+// nopHandler is enabled from Info and drops records, it measures the cost
+// of building records without the cost of formatting them.
+type nopHandler struct{}
+
+func (nopHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h nopHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h nopHandler) WithGroup(string) slog.Handler { return h }
+
+func (nopHandler) Handle(context.Context, slog.Record) error { return nil }
+
+// The values are variables, so the calls box them like calls with
+// values computed at runtime do.
+var (
+	perfUserID  = 4242
+	perfPath    = "/users"
+	perfOK      = true
+	perfElapsed = 1500 * time.Millisecond
+	perfDigit   = 7
+	errPerf     = errors.New("perf")
+)
+
+func perfBody() string {
+	return "body"
+}
+
+type perfCase struct {
+	name string
+	alog func(ctx context.Context)
+	slog func(ctx context.Context, logger *slog.Logger)
+	// boxed is the number of values boxed into args at the call site
+	// before alog is called, like for slog.
+	boxed float64
+	// budget is the number of allocations alog is allowed per call with
+	// nopHandler, besides the boxed values.
+	budget float64
+}
+
+// perfCases are the logging calls of the benchmark suite. Values passed as
+// args are boxed at the call site, one allocation for every value that does
+// not fit the runtime static values. Values passed as slog.Attr by LogAttrs
+// are not boxed.
+var perfCases = []perfCase{
+	{
+		name: "disabled",
+		alog: func(ctx context.Context) {
+			alog.Debug(ctx, "msg", "user_id", perfUserID, "body", alog.Lazy(perfBody))
+		},
+		slog: func(ctx context.Context, logger *slog.Logger) {
+			logger.DebugContext(ctx, "msg", "user_id", perfUserID, "body", alog.Lazy(perfBody))
+		},
+		// user_id
+		boxed:  1,
+		budget: 0,
+	},
+	{
+		name: "disabled_attrs",
+		alog: func(ctx context.Context) {
+			alog.LogAttrs(ctx, slog.LevelDebug, "msg", slog.Int("user_id", perfUserID))
+		},
+		slog: func(ctx context.Context, logger *slog.Logger) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "msg", slog.Int("user_id", perfUserID))
+		},
+		budget: 0,
+	},
+	{
+		name: "no_args",
+		alog: func(ctx context.Context) {
+			alog.Info(ctx, "msg")
+		},
+		slog: func(ctx context.Context, logger *slog.Logger) {
+			logger.InfoContext(ctx, "msg")
+		},
+		budget: 0,
+	},
+	{
+		name: "scalars",
+		alog: func(ctx context.Context) {
+			alog.Info(ctx, "msg", "user_id", perfUserID, "path", perfPath, "ok", perfOK, "elapsed", perfElapsed)
+		},
+		slog: func(ctx context.Context, logger *slog.Logger) {
+			logger.InfoContext(ctx, "msg", "user_id", perfUserID, "path", perfPath, "ok", perfOK, "elapsed", perfElapsed)
+		},
+		// user_id, path and elapsed, booleans are not allocated
+		boxed:  3,
+		budget: 0,
+	},
+	{
+		name: "scalar_attrs",
+		alog: func(ctx context.Context) {
+			alog.LogAttrs(ctx, slog.LevelInfo, "msg",
+				slog.Int("user_id", perfUserID),
+				slog.String("path", perfPath),
+				slog.Bool("ok", perfOK),
+				slog.Duration("elapsed", perfElapsed),
+			)
+		},
+		slog: func(ctx context.Context, logger *slog.Logger) {
+			logger.LogAttrs(ctx, slog.LevelInfo, "msg",
+				slog.Int("user_id", perfUserID),
+				slog.String("path", perfPath),
+				slog.Bool("ok", perfOK),
+				slog.Duration("elapsed", perfElapsed),
+			)
+		},
+		budget: 0,
+	},
+	{
+		name: "ten_args",
+		alog: func(ctx context.Context) {
+			d := perfDigit
+
+			alog.Info(ctx, "msg", "a", d, "b", d, "c", d, "d", d, "e", d, "f", d, "g", d, "h", d, "i", d, "j", d)
+		},
+		slog: func(ctx context.Context, logger *slog.Logger) {
+			d := perfDigit
+
+			logger.InfoContext(ctx, "msg", "a", d, "b", d, "c", d, "d", d, "e", d, "f", d, "g", d, "h", d, "i", d, "j", d)
+		},
+		// the record stores attributes after the fifth in one heap slice,
+		// digits are runtime static values
+		budget: 1,
+	},
+	{
+		name: "error",
+		alog: func(ctx context.Context) {
+			alog.Info(ctx, "msg", errPerf)
+		},
+		slog: func(ctx context.Context, logger *slog.Logger) {
+			logger.InfoContext(ctx, "msg", alog.ErrorKey, errPerf)
+		},
+		budget: 0,
+	},
+}
+
+func perfContext(ctx context.Context) context.Context {
+	ctx = alog.Context(ctx, nopHandler{})
+	ctx = alog.With(ctx, "request_id", "1")
+
+	return alog.WithGroup(ctx, "req")
+}
+
+func perfLogger() *slog.Logger {
+	return slog.New(nopHandler{}).With("request_id", "1").WithGroup("req")
+}
+
+// BenchmarkLog compares alog with slog.Logger methods with a context.
+func BenchmarkLog(b *testing.B) {
+	for _, pc := range perfCases {
+		b.Run(pc.name+"/alog", func(b *testing.B) {
+			ctx := perfContext(b.Context())
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				pc.alog(ctx)
+			}
+		})
+
+		b.Run(pc.name+"/slog", func(b *testing.B) {
+			logger := perfLogger()
+			ctx := b.Context()
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				pc.slog(ctx, logger)
+			}
+		})
+	}
+}
+
+// BenchmarkLog_WithoutContext measures slog.Logger methods without a context.
+func BenchmarkLog_WithoutContext(b *testing.B) {
+	logger := perfLogger()
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		logger.Info("msg", "user_id", perfUserID, "path", perfPath)
+	}
+}
+
+func startFinish(ctx context.Context) {
+	op := alog.Start(ctx, "op", "user_id", perfUserID)
+	op.Info("msg")
+	op.Finish()
+}
+
+// BenchmarkOperation measures an operation with one record.
+func BenchmarkOperation(b *testing.B) {
+	ctx := perfContext(b.Context())
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		startFinish(ctx)
+	}
+}
+
+func skipAllocsWithRace(t *testing.T) {
+	t.Helper()
+
+	if raceEnabled {
+		t.Skip("allocation budgets are not checked with the race detector")
+	}
+}
+
+func Test_Log_Allocs(t *testing.T) {
+	skipAllocsWithRace(t)
+
+	ctx := perfContext(t.Context())
+	logger := perfLogger()
+
+	for _, pc := range perfCases {
+		t.Run(pc.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(100, func() {
+				pc.alog(ctx)
+			})
+
+			slogAllocs := testing.AllocsPerRun(100, func() {
+				pc.slog(ctx, logger)
+			})
+
+			if allocs > pc.boxed+pc.budget || allocs > slogAllocs {
+				t.Fatalf("alog allocates %v times, %v boxed values, budget %v, slog allocates %v times",
+					allocs, pc.boxed, pc.budget, slogAllocs,
+				)
+			}
+		})
+	}
+}
+
+func Test_Err_Allocs(t *testing.T) {
+	skipAllocsWithRace(t)
+
+	ctx := perfContext(t.Context())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = alog.Err(ctx, errPerf, "failed", "user_id", perfDigit)
+	})
+
+	if allocs > 0 {
+		t.Fatalf("alog.Err allocates %v times, budget 0", allocs)
+	}
+}
+
+func Test_Operation_Allocs(t *testing.T) {
+	skipAllocsWithRace(t)
+
+	ctx := perfContext(t.Context())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		startFinish(ctx)
+	})
+
+	// user_id, the operation attributes and their context node, the event
+	// and the two contexts storing them
+	const budget = 6
+
+	if allocs > budget {
+		t.Fatalf("operation allocates %v times, budget %v", allocs, budget)
+	}
+}
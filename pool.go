@@ -0,0 +1,35 @@
+package alog
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// maxPooledAttrs is the capacity above which scratch buffers are not pooled,
+// so a single large record does not keep a large buffer alive.
+const maxPooledAttrs = 64
+
+var attrsPool = sync.Pool{
+	New: func() any {
+		attrs := make([]slog.Attr, 0, 16)
+
+		return &attrs
+	},
+}
+
+// getAttrs returns an empty scratch buffer for record attributes,
+// it must be returned by putAttrs once the record is handled.
+func getAttrs() *[]slog.Attr {
+	return attrsPool.Get().(*[]slog.Attr)
+}
+
+func putAttrs(attrs *[]slog.Attr) {
+	if cap(*attrs) > maxPooledAttrs {
+		return
+	}
+
+	clear(*attrs)
+	*attrs = (*attrs)[:0]
+
+	attrsPool.Put(attrs)
+}
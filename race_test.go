@@ -0,0 +1,6 @@
+//go:build race
+
+package alog_test
+
+// raceEnabled disables allocation budgets, the race detector allocates.
+const raceEnabled = true
@@ -0,0 +1,120 @@
+package alog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+const PanicMsg = "panic"
+
+const (
+	PanicKey     = "panic"
+	PanicTypeKey = "panic_type"
+	StackKey     = "stack"
+)
+
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+
+	return err
+}
+
+// Recover recovers a panic and logs it at Error, it must be called directly by defer.
+func Recover(ctx context.Context) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	logPanic(ctx, v)
+}
+
+// Repanic logs a panic at Error and panics again with the same value,
+// it must be called directly by defer.
+func Repanic(ctx context.Context) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	logPanic(ctx, v)
+
+	panic(v)
+}
+
+// RecoverError recovers a panic, logs it at Error and stores it in errp as
+// *PanicError, it must be called directly by defer.
+func RecoverError(ctx context.Context, errp *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	*errp = logPanic(ctx, v)
+}
+
+// Go runs fn in a new goroutine with ctx and logs a panic in fn instead of crashing.
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	go func() {
+		defer Recover(ctx)
+
+		fn(ctx)
+	}()
+}
+
+func logPanic(ctx context.Context, v any) *PanicError {
+	err := &PanicError{
+		Value: v,
+		Stack: debug.Stack(),
+	}
+
+	alogPC(ctx, panicPC(), slog.LevelError, PanicMsg,
+		slog.Any(PanicKey, v),
+		slog.String(PanicTypeKey, fmt.Sprintf("%T", v)),
+		slog.String(StackKey, string(err.Stack)),
+	)
+
+	return err
+}
+
+// panicPC returns the PC of the function that panicked, it is the first
+// non-runtime frame after the runtime panic frames.
+func panicPC() uintptr {
+	const maxDepth = 64
+
+	pcs := make([]uintptr, maxDepth)
+	n := runtime.Callers(2, pcs)
+
+	frames := runtime.CallersFrames(pcs[:n])
+	inRuntime := false
+
+	for {
+		frame, more := frames.Next()
+
+		isRuntime := strings.HasPrefix(frame.Function, "runtime.")
+
+		switch {
+		case isRuntime:
+			inRuntime = true
+		case inRuntime:
+			return frame.PC
+		}
+
+		if !more {
+			return 0
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package alog_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/amidgo/alog"
+)
+
+type capturedRecord struct {
+	record slog.Record
+	attrs  map[string]slog.Value
+}
+
+type captureHandler struct {
+	mu      *sync.Mutex
+	attrs   []slog.Attr
+	records *[]capturedRecord
+}
+
+func newCaptureHandler() captureHandler {
+	return captureHandler{
+		mu:      &sync.Mutex{},
+		records: &[]capturedRecord{},
+	}
+}
+
+func (h captureHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h captureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)
+
+	return h
+}
+
+func (h captureHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func (h captureHandler) Handle(_ context.Context, record slog.Record) error {
+	attrs := make(map[string]slog.Value)
+
+	for _, attr := range h.attrs {
+		attrs[attr.Key] = attr.Value
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value
+
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	*h.records = append(*h.records, capturedRecord{record: record.Clone(), attrs: attrs})
+
+	return nil
+}
+
+func (h captureHandler) Records() []capturedRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]capturedRecord(nil), *h.records...)
+}
+
+func recordFunction(record slog.Record) string {
+	frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+
+	return frame.Function
+}
+
+func assertPanicRecord(t *testing.T, rec capturedRecord, value, valueType, function string) {
+	t.Helper()
+
+	if rec.record.Message != alog.PanicMsg || rec.record.Level != slog.LevelError {
+		t.Fatalf("unexpected record %s %s", rec.record.Level, rec.record.Message)
+	}
+
+	if rec.attrs[alog.PanicKey].String() != value {
+		t.Fatalf("unexpected panic value %q", rec.attrs[alog.PanicKey])
+	}
+
+	if rec.attrs[alog.PanicTypeKey].String() != valueType {
+		t.Fatalf("unexpected panic type %q", rec.attrs[alog.PanicTypeKey])
+	}
+
+	if !strings.Contains(rec.attrs[alog.StackKey].String(), function) {
+		t.Fatalf("stack does not contain %s\n%s", function, rec.attrs[alog.StackKey])
+	}
+
+	if !strings.HasSuffix(recordFunction(rec.record), function) {
+		t.Fatalf("unexpected record source %s, expected %s", recordFunction(rec.record), function)
+	}
+}
+
+func panicValue(ctx context.Context) {
+	defer alog.Recover(ctx)
+
+	panic("boom")
+}
+
+func panicNilPointer(ctx context.Context) {
+	defer alog.Recover(ctx)
+
+	var p *int
+
+	_ = *p
+}
+
+func Test_Recover(t *testing.T) {
+	h := newCaptureHandler()
+
+	ctx := alog.Context(t.Context(), h)
+	op := alog.Start(ctx, "job")
+
+	panicValue(op.Context())
+	panicNilPointer(op.Context())
+
+	records := h.Records()
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, actual %d", len(records))
+	}
+
+	assertPanicRecord(t, records[1], "boom", "string", "alog_test.panicValue")
+	assertPanicRecord(t, records[2],
+		"runtime error: invalid memory address or nil pointer dereference",
+		"runtime.errorString",
+		"alog_test.panicNilPointer",
+	)
+
+	if records[1].attrs[alog.OpKey].String() != "job" {
+		t.Fatal("panic record has no operation attribute")
+	}
+}
+
+func Test_Recover_NoPanic(t *testing.T) {
+	h := newCaptureHandler()
+
+	ctx := alog.Context(t.Context(), h)
+
+	func() {
+		defer alog.Recover(ctx)
+	}()
+
+	if len(h.Records()) != 0 {
+		t.Fatal("unexpected records")
+	}
+}
+
+func Test_Repanic(t *testing.T) {
+	h := newCaptureHandler()
+
+	ctx := alog.Context(t.Context(), h)
+
+	defer func() {
+		v := recover()
+		if v != io.EOF {
+			t.Fatalf("unexpected panic value %v", v)
+		}
+
+		records := h.Records()
+		if len(records) != 1 {
+			t.Fatalf("expected 1 record, actual %d", len(records))
+		}
+
+		assertPanicRecord(t, records[0], io.EOF.Error(), "*errors.errorString", "alog_test.repanic")
+	}()
+
+	repanic(ctx)
+}
+
+func repanic(ctx context.Context) {
+	defer alog.Repanic(ctx)
+
+	panic(io.EOF)
+}
+
+func Test_RecoverError(t *testing.T) {
+	h := newCaptureHandler()
+
+	ctx := alog.Context(t.Context(), h)
+
+	err := recoverError(ctx)
+
+	var panicErr *alog.PanicError
+
+	if !errors.As(err, &panicErr) || panicErr.Value != io.EOF || len(panicErr.Stack) == 0 {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !errors.Is(err, io.EOF) {
+		t.Fatal("panic error does not unwrap panic value")
+	}
+
+	if err.Error() != "panic: EOF" {
+		t.Fatalf("unexpected error message %q", err)
+	}
+
+	assertPanicRecord(t, h.Records()[0], io.EOF.Error(), "*errors.errorString", "alog_test.recoverError")
+}
+
+func recoverError(ctx context.Context) (err error) {
+	defer alog.RecoverError(ctx, &err)
+
+	panic(io.EOF)
+}
+
+func Test_Go(t *testing.T) {
+	h := newCaptureHandler()
+
+	ctx := alog.Context(t.Context(), h)
+	ctx = alog.With(ctx, "request_id", "1")
+
+	done := make(chan struct{})
+
+	alog.Go(ctx, func(ctx context.Context) {
+		defer close(done)
+
+		if alog.Handler(ctx) == slog.Default().Handler() {
+			t.Error("goroutine context has no handler")
+		}
+
+		alog.Info(ctx, "in goroutine")
+	})
+
+	<-done
+
+	panicked := make(chan struct{})
+
+	alog.Go(ctx, func(context.Context) {
+		defer close(panicked)
+
+		panic("boom")
+	})
+
+	<-panicked
+
+	deadline := make(chan struct{})
+
+	go func() {
+		defer close(deadline)
+
+		for len(h.Records()) < 2 {
+			runtime.Gosched()
+		}
+	}()
+
+	<-deadline
+
+	records := h.Records()
+
+	if records[0].record.Message != "in goroutine" || records[0].attrs["request_id"].String() != "1" {
+		t.Fatalf("unexpected record %+v", records[0])
+	}
+
+	assertPanicRecord(t, records[1], "boom", "string", "alog_test.Test_Go.func2")
+}
@@ -0,0 +1,281 @@
+package alog
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const registryRecentSize = 16
+
+type registryKey struct{}
+
+func WithRegistry(ctx context.Context, r *Registry) context.Context {
+	if r == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, registryKey{}, r)
+}
+
+func registryFromContext(ctx context.Context) *Registry {
+	r, _ := ctx.Value(registryKey{}).(*Registry)
+
+	return r
+}
+
+// Registry tracks operations started with a context returned by WithRegistry.
+type Registry struct {
+	mu     sync.Mutex
+	nextID uint64
+	active map[uint64]*activeOperation
+	stats  map[string]*operationStats
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		active: make(map[uint64]*activeOperation),
+		stats:  make(map[string]*operationStats),
+	}
+}
+
+type activeOperation struct {
+	id    uint64
+	name  string
+	attrs []slog.Attr
+	clock Clock
+	start time.Time
+}
+
+type operationStats struct {
+	completed uint64
+	failed    uint64
+	latencies []time.Duration
+	errors    []OperationError
+}
+
+type ActiveOperation struct {
+	ID      uint64            `json:"id"`
+	Start   time.Time         `json:"start"`
+	Elapsed time.Duration     `json:"elapsed_ns"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
+
+type OperationError struct {
+	Time    time.Time     `json:"time"`
+	Elapsed time.Duration `json:"elapsed_ns"`
+	Error   string        `json:"error"`
+}
+
+type OperationStats struct {
+	Name            string            `json:"name"`
+	Active          []ActiveOperation `json:"active"`
+	Completed       uint64            `json:"completed"`
+	Failed          uint64            `json:"failed"`
+	RecentLatencies []time.Duration   `json:"recent_latencies_ns"`
+	RecentErrors    []OperationError  `json:"recent_errors"`
+}
+
+type registration struct {
+	registry *Registry
+	op       *activeOperation
+	once     sync.Once
+}
+
+func (r *Registry) start(name string, attrs []slog.Attr, clock Clock, start time.Time) *registration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+
+	op := &activeOperation{
+		id:    r.nextID,
+		name:  name,
+		attrs: attrs,
+		clock: clock,
+		start: start,
+	}
+
+	r.active[op.id] = op
+
+	return &registration{
+		registry: r,
+		op:       op,
+	}
+}
+
+func (reg *registration) end(err error) {
+	if reg == nil {
+		return
+	}
+
+	reg.once.Do(func() {
+		reg.registry.end(reg.op, err)
+	})
+}
+
+func (r *Registry) end(op *activeOperation, err error) {
+	now := op.clock.Now()
+	elapsed := now.Sub(op.start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.active, op.id)
+
+	stats := r.operationStats(op.name)
+
+	stats.completed++
+	stats.latencies = appendRecent(stats.latencies, elapsed)
+
+	if err == nil {
+		return
+	}
+
+	stats.failed++
+	stats.errors = appendRecent(stats.errors,
+		OperationError{
+			Time:    now,
+			Elapsed: elapsed,
+			Error:   err.Error(),
+		},
+	)
+}
+
+func (r *Registry) operationStats(name string) *operationStats {
+	stats, ok := r.stats[name]
+	if !ok {
+		stats = &operationStats{}
+		r.stats[name] = stats
+	}
+
+	return stats
+}
+
+func appendRecent[T any](recent []T, v T) []T {
+	if len(recent) == registryRecentSize {
+		recent = slices.Delete(recent, 0, 1)
+	}
+
+	return append(recent, v)
+}
+
+func (r *Registry) Snapshot() []OperationStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byName := make(map[string]*OperationStats, len(r.stats))
+
+	snapshot := func(name string) *OperationStats {
+		s, ok := byName[name]
+		if !ok {
+			s = &OperationStats{Name: name}
+			byName[name] = s
+		}
+
+		return s
+	}
+
+	for name, stats := range r.stats {
+		s := snapshot(name)
+
+		s.Completed = stats.completed
+		s.Failed = stats.failed
+		s.RecentLatencies = slices.Clone(stats.latencies)
+		s.RecentErrors = slices.Clone(stats.errors)
+	}
+
+	for _, op := range r.active {
+		s := snapshot(op.name)
+
+		s.Active = append(s.Active,
+			ActiveOperation{
+				ID:      op.id,
+				Start:   op.start,
+				Elapsed: op.clock.Now().Sub(op.start),
+				Attrs:   attrsMap(op.attrs),
+			},
+		)
+	}
+
+	result := make([]OperationStats, 0, len(byName))
+
+	for _, s := range byName {
+		slices.SortFunc(s.Active, func(a, b ActiveOperation) int { return cmp.Compare(a.ID, b.ID) })
+
+		result = append(result, *s)
+	}
+
+	slices.SortFunc(result, func(a, b OperationStats) int { return strings.Compare(a.Name, b.Name) })
+
+	return result
+}
+
+func attrsMap(attrs []slog.Attr) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(attrs))
+
+	for _, attr := range attrs {
+		m[attr.Key] = attr.Value.Resolve().String()
+	}
+
+	return m
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	snapshot := r.Snapshot()
+
+	if wantJSON(req) {
+		w.Header().Set("Content-Type", "application/json")
+
+		_ = json.NewEncoder(w).Encode(snapshot)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	_ = registryTemplate.Execute(w, snapshot)
+}
+
+func wantJSON(req *http.Request) bool {
+	if req.URL.Query().Get("format") == "json" {
+		return true
+	}
+
+	return strings.Contains(req.Header.Get("Accept"), "application/json")
+}
+
+var registryTemplate = template.Must(template.New("registry").Parse(`<!DOCTYPE html>
+<html>
+<head><title>alog operations</title></head>
+<body>
+<h1>Operations</h1>
+<table border="1" cellpadding="4">
+<tr><th>Name</th><th>Active</th><th>Completed</th><th>Failed</th><th>Recent latencies</th></tr>
+{{range .}}<tr><td><a href="#{{.Name}}">{{.Name}}</a></td><td>{{len .Active}}</td><td>{{.Completed}}</td><td>{{.Failed}}</td><td>{{range .RecentLatencies}}{{.}} {{end}}</td></tr>
+{{end}}</table>
+{{range .}}<h2 id="{{.Name}}">{{.Name}}</h2>
+{{if .Active}}<h3>Active</h3>
+<table border="1" cellpadding="4">
+<tr><th>ID</th><th>Start</th><th>Elapsed</th><th>Attributes</th></tr>
+{{range .Active}}<tr><td>{{.ID}}</td><td>{{.Start.Format "2006-01-02T15:04:05.000Z07:00"}}</td><td>{{.Elapsed}}</td><td>{{range $k, $v := .Attrs}}{{$k}}={{$v}} {{end}}</td></tr>
+{{end}}</table>
+{{end}}{{if .RecentErrors}}<h3>Recent errors</h3>
+<table border="1" cellpadding="4">
+<tr><th>Time</th><th>Elapsed</th><th>Error</th></tr>
+{{range .RecentErrors}}<tr><td>{{.Time.Format "2006-01-02T15:04:05.000Z07:00"}}</td><td>{{.Elapsed}}</td><td>{{.Error}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}</body>
+</html>
+`))
@@ -0,0 +1,166 @@
+package alog_test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amidgo/alog"
+)
+
+func Test_Registry(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	opts := &alog.StartOptions{Clock: clock}
+
+	reg := alog.NewRegistry()
+
+	ctx := alog.Context(t.Context(), slog.NewTextHandler(io.Discard, nil))
+	ctx = alog.WithRegistry(ctx, reg)
+
+	first := alog.StartWithOptions(ctx, "query", opts, "table", "users")
+	clock.Advance(time.Second)
+	first.Finish()
+
+	failed := alog.StartWithOptions(ctx, "query", opts, "table", "orders")
+	clock.Advance(2 * time.Second)
+	failed.Error(io.ErrUnexpectedEOF)
+	failed.Finish()
+
+	running := alog.StartWithOptions(ctx, "query", opts, "table", "items")
+	hanging := alog.StartWithOptions(ctx, "rpc", opts)
+	clock.Advance(3 * time.Second)
+
+	expected := []alog.OperationStats{
+		{
+			Name: "query",
+			Active: []alog.ActiveOperation{
+				{
+					ID:      3,
+					Start:   start.Add(3 * time.Second),
+					Elapsed: 3 * time.Second,
+					Attrs:   map[string]string{"table": "items"},
+				},
+			},
+			Completed:       2,
+			Failed:          1,
+			RecentLatencies: []time.Duration{time.Second, 2 * time.Second},
+			RecentErrors: []alog.OperationError{
+				{
+					Time:    start.Add(3 * time.Second),
+					Elapsed: 2 * time.Second,
+					Error:   io.ErrUnexpectedEOF.Error(),
+				},
+			},
+		},
+		{
+			Name: "rpc",
+			Active: []alog.ActiveOperation{
+				{
+					ID:      4,
+					Start:   start.Add(3 * time.Second),
+					Elapsed: 3 * time.Second,
+				},
+			},
+		},
+	}
+
+	snapshot := reg.Snapshot()
+
+	if !reflect.DeepEqual(expected, snapshot) {
+		t.Fatalf("snapshot not equal\nexpected:\n%+v\nactual:\n%+v", expected, snapshot)
+	}
+
+	running.Finish()
+	hanging.Finish()
+
+	for _, stats := range reg.Snapshot() {
+		if len(stats.Active) != 0 {
+			t.Fatalf("operation %s has active operations after finish", stats.Name)
+		}
+	}
+}
+
+func Test_Registry_RecentLimit(t *testing.T) {
+	reg := alog.NewRegistry()
+
+	ctx := alog.Context(t.Context(), slog.NewTextHandler(io.Discard, nil))
+	ctx = alog.WithRegistry(ctx, reg)
+
+	for range 100 {
+		alog.Start(ctx, "op").Error(io.ErrUnexpectedEOF)
+	}
+
+	stats := reg.Snapshot()[0]
+
+	if stats.Completed != 100 || stats.Failed != 100 {
+		t.Fatalf("unexpected counts, completed %d, failed %d", stats.Completed, stats.Failed)
+	}
+
+	if len(stats.RecentLatencies) > 16 || len(stats.RecentErrors) > 16 {
+		t.Fatalf("recent values are not limited, latencies %d, errors %d", len(stats.RecentLatencies), len(stats.RecentErrors))
+	}
+}
+
+func Test_Registry_WithoutRegistry(t *testing.T) {
+	ctx := alog.Context(t.Context(), slog.NewTextHandler(io.Discard, nil))
+
+	if alog.WithRegistry(ctx, nil) != ctx {
+		t.Fatal("context has been modified")
+	}
+
+	alog.Start(ctx, "op").Finish()
+}
+
+func Test_Registry_ServeHTTP(t *testing.T) {
+	reg := alog.NewRegistry()
+
+	ctx := alog.Context(t.Context(), slog.NewTextHandler(io.Discard, nil))
+	ctx = alog.WithRegistry(ctx, reg)
+
+	op := alog.Start(ctx, "<script>", "user", "amidman")
+	defer op.Finish()
+
+	alog.Start(ctx, "failed").Error(io.ErrUnexpectedEOF)
+
+	t.Run("html", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/operations", nil))
+
+		body := rec.Body.String()
+
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+			t.Fatalf("unexpected content type %q", rec.Header().Get("Content-Type"))
+		}
+
+		for _, expected := range []string{"&lt;script&gt;", "user=amidman", "failed", io.ErrUnexpectedEOF.Error()} {
+			if !strings.Contains(body, expected) {
+				t.Fatalf("body does not contain %q\n%s", expected, body)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/operations?format=json", nil))
+
+		var stats []alog.OperationStats
+
+		err := json.Unmarshal(rec.Body.Bytes(), &stats)
+		if err != nil {
+			t.Fatalf("unmarshal json: %s", err)
+		}
+
+		if len(stats) != 2 || stats[0].Name != "<script>" || len(stats[0].Active) != 1 || stats[1].Failed != 1 {
+			t.Fatalf("unexpected stats %+v", stats)
+		}
+	})
+}
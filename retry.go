@@ -0,0 +1,180 @@
+package alog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const AttemptOp = "attempt"
+
+const (
+	AttemptKey  = "attempt"
+	AttemptsKey = "attempts"
+	DelayKey    = "delay"
+)
+
+// Backoff returns the delay before the next attempt after the given failed attempt,
+// attempts are numbered from 1.
+type Backoff func(attempt int) time.Duration
+
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the initial delay after every failed attempt up to maxDelay,
+// the delay is not limited if maxDelay is zero.
+func ExponentialBackoff(initial, maxDelay time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := initial
+
+		for range attempt - 1 {
+			if (maxDelay > 0 && delay >= maxDelay) || delay > math.MaxInt64/2 {
+				break
+			}
+
+			delay *= 2
+		}
+
+		if maxDelay > 0 {
+			delay = min(delay, maxDelay)
+		}
+
+		return delay
+	}
+}
+
+// JitterBackoff randomly reduces the delay of backoff by up to fraction of it,
+// fraction 1 gives a random delay between zero and the backoff delay.
+func JitterBackoff(backoff Backoff, fraction float64) Backoff {
+	fraction = min(max(fraction, 0), 1)
+
+	return func(attempt int) time.Duration {
+		delay := backoff(attempt)
+
+		return delay - time.Duration(rand.Float64()*fraction*float64(delay))
+	}
+}
+
+type RetryOptions struct {
+	// MaxAttempts is the number of attempts including the first one, 3 if zero.
+	MaxAttempts int
+	// Backoff is ExponentialBackoff(100*time.Millisecond, 10*time.Second) if nil.
+	Backoff Backoff
+	// Retryable reports whether a failed attempt is retried,
+	// all errors except context cancellation and deadline errors are retried if nil.
+	Retryable func(err error) bool
+	Clock     Clock
+}
+
+func retryOptionsMaxAttempts(opts *RetryOptions) int {
+	if opts != nil && opts.MaxAttempts > 0 {
+		return opts.MaxAttempts
+	}
+
+	return 3
+}
+
+func retryOptionsBackoff(opts *RetryOptions) Backoff {
+	if opts != nil && opts.Backoff != nil {
+		return opts.Backoff
+	}
+
+	return ExponentialBackoff(100*time.Millisecond, 10*time.Second)
+}
+
+func retryOptionsRetryable(opts *RetryOptions) func(err error) bool {
+	if opts != nil && opts.Retryable != nil {
+		return opts.Retryable
+	}
+
+	return retryable
+}
+
+func retryable(err error) bool {
+	_, canceled := cancellationOutcome(err)
+
+	return !canceled
+}
+
+func retryOptionsClock(opts *RetryOptions) Clock {
+	if opts != nil && opts.Clock != nil {
+		return opts.Clock
+	}
+
+	return systemClock{}
+}
+
+// Retry runs fn as an operation with every attempt logged as a child
+// operation with the attempt number and the delay before it. It returns
+// nil on the first successful attempt, otherwise it logs the last error
+// through Operation.Error and returns it. When ctx is done while waiting
+// for the next attempt, the returned error joins the context cause and the
+// last attempt error. The final outcome is logged under every LoggedPolicy,
+// with the already logged attempt error referenced by record ID.
+func Retry(
+	ctx context.Context,
+	opName string,
+	opts *RetryOptions,
+	fn func(ctx context.Context) error,
+	additionalArgs ...any,
+) error {
+	pc := callerPC(1)
+
+	maxAttempts := retryOptionsMaxAttempts(opts)
+	backoff := retryOptionsBackoff(opts)
+	isRetryable := retryOptionsRetryable(opts)
+
+	startOpts := &StartOptions{Clock: retryOptionsClock(opts)}
+
+	op := startPC(ctx, pc, opName, startOpts, additionalArgs)
+
+	var delay time.Duration
+
+	for attempt := 1; ; attempt++ {
+		attemptOp := startPC(op.ctx, pc, AttemptOp, startOpts, nil, slog.Int(AttemptKey, attempt), slog.Duration(DelayKey, delay))
+
+		err := fn(attemptOp.Context())
+		if err == nil {
+			attemptOp.finishPC(pc, nil)
+			op.finishPC(pc, nil, slog.Int(AttemptsKey, attempt))
+
+			return nil
+		}
+
+		err = attemptOp.errorPC(pc, err, nil)
+
+		if attempt >= maxAttempts || !isRetryable(err) {
+			return op.outcomePC(pc, err, nil, slog.Int(AttemptsKey, attempt))
+		}
+
+		delay = backoff(attempt)
+
+		waitErr := wait(op.ctx, startOpts.Clock, delay)
+		if waitErr != nil {
+			return op.outcomePC(pc, errors.Join(waitErr, err), nil, slog.Int(AttemptsKey, attempt))
+		}
+	}
+}
+
+func wait(ctx context.Context, clock Clock, delay time.Duration) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	timer := clock.NewTimer(delay)
+
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+
+		return context.Cause(ctx)
+	case <-timer.C():
+		return nil
+	}
+}
@@ -0,0 +1,252 @@
+package alog_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func attemptOp(op alogtest.Op, attempt int, delay time.Duration) alogtest.Op {
+	return op.Op(alog.AttemptOp, alog.AttemptKey, attempt, alog.DelayKey, delay)
+}
+
+func failingAttempts(failures int, err error) func(context.Context) error {
+	attempts := 0
+
+	return func(context.Context) error {
+		attempts++
+
+		if attempts <= failures {
+			return err
+		}
+
+		return nil
+	}
+}
+
+func runRetry(ctx context.Context, opts *alog.RetryOptions, fn func(context.Context) error) <-chan error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- alog.Retry(ctx, "fetch", opts, fn, "url", "/users")
+	}()
+
+	return errCh
+}
+
+func Test_Retry(t *testing.T) {
+	clock := newFakeClock()
+
+	op := alogtest.NewOp("fetch", "url", "/users")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		attemptOp(op, 1, 0).Start(),
+		attemptOp(op, 1, 0).Error(io.ErrUnexpectedEOF),
+		attemptOp(op, 2, time.Second).Start(),
+		attemptOp(op, 2, time.Second).Error(io.ErrUnexpectedEOF),
+		attemptOp(op, 3, 2*time.Second).Start(),
+		attemptOp(op, 3, 2*time.Second).Finish(),
+		op.Finish(alog.AttemptsKey, 3),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	opts := &alog.RetryOptions{
+		Backoff: alog.ExponentialBackoff(time.Second, 0),
+		Clock:   clock,
+	}
+
+	errCh := runRetry(ctx, opts, failingAttempts(2, io.ErrUnexpectedEOF))
+
+	clock.WaitTimers(1)
+	clock.Advance(time.Second)
+	clock.WaitTimers(2)
+	clock.Advance(2 * time.Second)
+
+	err := <-errCh
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func Test_Retry_MaxAttempts(t *testing.T) {
+	clock := newFakeClock()
+
+	op := alogtest.NewOp("fetch", "url", "/users")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		attemptOp(op, 1, 0).Start(),
+		attemptOp(op, 1, 0).Error(io.ErrUnexpectedEOF),
+		attemptOp(op, 2, time.Second).Start(),
+		attemptOp(op, 2, time.Second).Error(io.ErrUnexpectedEOF),
+		op.Error(io.ErrUnexpectedEOF, alog.AttemptsKey, 2),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	opts := &alog.RetryOptions{
+		MaxAttempts: 2,
+		Backoff:     alog.ConstantBackoff(time.Second),
+		Clock:       clock,
+	}
+
+	errCh := runRetry(ctx, opts, failingAttempts(3, io.ErrUnexpectedEOF))
+
+	clock.WaitTimers(1)
+	clock.Advance(time.Second)
+
+	err := <-errCh
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func Test_Retry_NotRetryable(t *testing.T) {
+	op := alogtest.NewOp("fetch", "url", "/users")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		attemptOp(op, 1, 0).Start(),
+		attemptOp(op, 1, 0).Error(errNotFound),
+		op.Error(errNotFound, alog.AttemptsKey, 1),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	opts := &alog.RetryOptions{
+		Retryable: func(err error) bool {
+			return !errors.Is(err, errNotFound)
+		},
+		Clock: newFakeClock(),
+	}
+
+	err := <-runRetry(ctx, opts, failingAttempts(1, errNotFound))
+	if !errors.Is(err, errNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func Test_Retry_ContextCanceled(t *testing.T) {
+	clock := newFakeClock()
+
+	op := alogtest.NewOp("fetch", "url", "/users")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		attemptOp(op, 1, 0).Start(),
+		attemptOp(op, 1, 0).Error(io.ErrUnexpectedEOF),
+		op.ErrorOutcome(
+			slog.LevelWarn,
+			errors.Join(context.Canceled, io.ErrUnexpectedEOF),
+			alog.OutcomeCanceled,
+			alog.AttemptsKey, 1,
+			alog.ElapsedKey, time.Duration(0),
+		),
+	)
+
+	ctx, cancel := context.WithCancel(alog.Context(t.Context(), handler))
+
+	opts := &alog.RetryOptions{
+		Backoff: alog.ConstantBackoff(time.Second),
+		Clock:   clock,
+	}
+
+	errCh := runRetry(ctx, opts, failingAttempts(3, io.ErrUnexpectedEOF))
+
+	clock.WaitTimers(1)
+	cancel()
+
+	err := <-errCh
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if clock.active() != 0 {
+		t.Fatalf("backoff timer is not stopped")
+	}
+}
+
+func Test_Retry_CallerSource(t *testing.T) {
+	alogtest.AssertCallerSource(t, func(ctx context.Context) {
+		opts := &alog.RetryOptions{
+			Retryable: func(error) bool { return false },
+		}
+
+		_ = alog.Retry(ctx, "fetch", opts, func(context.Context) error { return io.EOF })
+		_ = alog.Retry(ctx, "fetch", opts, func(context.Context) error { return nil })
+	})
+}
+
+func Test_ExponentialBackoff(t *testing.T) {
+	backoff := alog.ExponentialBackoff(time.Second, 5*time.Second)
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+
+	for i, delay := range expected {
+		actual := backoff(i + 1)
+		if actual != delay {
+			t.Fatalf("attempt %d: expected delay %s, actual %s", i+1, delay, actual)
+		}
+	}
+}
+
+func Test_JitterBackoff(t *testing.T) {
+	backoff := alog.JitterBackoff(alog.ConstantBackoff(time.Second), 0.5)
+
+	for range 100 {
+		delay := backoff(1)
+		if delay < 500*time.Millisecond || delay > time.Second {
+			t.Fatalf("delay %s out of [500ms, 1s]", delay)
+		}
+	}
+}
+
+func Test_Retry_LoggedPolicy(t *testing.T) {
+	policies := map[string]alog.LoggedPolicy{
+		"skip":      alog.LoggedSkip,
+		"debug":     alog.LoggedDebug,
+		"reference": alog.LoggedReference,
+	}
+
+	for name, policy := range policies {
+		t.Run(name, func(t *testing.T) {
+			sequentialRecordIDs(t)
+
+			op := alogtest.NewOp("fetch", "url", "/users")
+
+			handler := alogtest.NewHandler(t,
+				(*alogtest.AssertOptions)(nil),
+				op.Start(),
+				attemptOp(op, 1, 0).Start(),
+				attemptOp(op, 1, 0).Error(errNotFound, alog.RecordIDKey, "id1"),
+				op.Log(slog.LevelError, alog.ErrorMsg, alog.LoggedKey, "id1", alog.AttemptsKey, 1),
+			)
+
+			ctx := alog.Context(t.Context(), handler)
+			ctx = alog.WithLoggedPolicy(ctx, policy)
+
+			opts := &alog.RetryOptions{
+				Retryable: func(error) bool { return false },
+			}
+
+			err := <-runRetry(ctx, opts, failingAttempts(1, errNotFound))
+
+			recordID, ok := alog.LoggedRecordID(err)
+			if !errors.Is(err, errNotFound) || !ok || recordID != "id1" {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
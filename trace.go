@@ -0,0 +1,243 @@
+package alog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+const TraceparentHeader = "traceparent"
+
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+const traceFlagSampled = 0x01
+
+// TraceContext is the trace ID, span ID and trace flags of a W3C traceparent.
+type TraceContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Flags   byte
+}
+
+// IsValid reports whether the trace ID and span ID are not all zeros.
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+func (tc TraceContext) TraceIDString() string {
+	return hex.EncodeToString(tc.TraceID[:])
+}
+
+func (tc TraceContext) SpanIDString() string {
+	return hex.EncodeToString(tc.SpanID[:])
+}
+
+// String returns the trace context in the traceparent format of version 00.
+func (tc TraceContext) String() string {
+	return "00-" + tc.TraceIDString() + "-" + tc.SpanIDString() + "-" + hex.EncodeToString([]byte{tc.Flags})
+}
+
+var errInvalidTraceparent = errors.New("invalid traceparent")
+
+// ParseTraceparent parses a W3C traceparent, versions after 00 are parsed
+// by their 00 prefix fields.
+func ParseTraceparent(traceparent string) (TraceContext, error) {
+	const (
+		version00Len = 55
+		versionLen   = 2
+	)
+
+	traceparent = strings.TrimSpace(traceparent)
+
+	if len(traceparent) < version00Len || !isLowerHex(traceparent[:versionLen]) {
+		return TraceContext{}, errInvalidTraceparent
+	}
+
+	version := traceparent[:versionLen]
+
+	switch {
+	case version == "ff":
+		return TraceContext{}, errInvalidTraceparent
+	case version == "00" && len(traceparent) != version00Len:
+		return TraceContext{}, errInvalidTraceparent
+	case len(traceparent) > version00Len && traceparent[version00Len] != '-':
+		return TraceContext{}, errInvalidTraceparent
+	}
+
+	parts := strings.Split(traceparent[:version00Len], "-")
+	if len(parts) != 4 {
+		return TraceContext{}, errInvalidTraceparent
+	}
+
+	tc := TraceContext{}
+
+	flags := [1]byte{}
+
+	for _, field := range []struct {
+		text string
+		dst  []byte
+	}{
+		{parts[1], tc.TraceID[:]},
+		{parts[2], tc.SpanID[:]},
+		{parts[3], flags[:]},
+	} {
+		if len(field.text) != 2*len(field.dst) || !isLowerHex(field.text) {
+			return TraceContext{}, errInvalidTraceparent
+		}
+
+		_, _ = hex.Decode(field.dst, []byte(field.text))
+	}
+
+	tc.Flags = flags[0]
+
+	if !tc.IsValid() {
+		return TraceContext{}, errInvalidTraceparent
+	}
+
+	return tc, nil
+}
+
+func isLowerHex(s string) bool {
+	for i := range len(s) {
+		c := s[i]
+
+		if ('0' > c || c > '9') && ('a' > c || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+type traceKey struct{}
+
+// traceState is the context trace, attrs are the trace attributes of
+// operations, they are nil for remote parents stored by WithTrace.
+type traceState struct {
+	tc    TraceContext
+	attrs []slog.Attr
+}
+
+func localTraceState(tc TraceContext) traceState {
+	return traceState{
+		tc: tc,
+		attrs: []slog.Attr{
+			slog.String(TraceIDKey, tc.TraceIDString()),
+			slog.String(SpanIDKey, tc.SpanIDString()),
+		},
+	}
+}
+
+// traceAttrs returns trace_id and span_id of the current operation, the
+// root node adds them to the handler, so records of nested operations have
+// a single span_id and groups do not apply to them.
+func traceAttrs(ctx context.Context) []slog.Attr {
+	state, _ := ctx.Value(traceKey{}).(traceState)
+
+	return state.attrs
+}
+
+// withTrace adds the trace attributes of state to the root nodes of the
+// default and named channels.
+func withTrace(ctx context.Context, state traceState) context.Context {
+	ctx = context.WithValue(ctx, traceKey{}, state)
+
+	node := attrsNodeFromContext(ctx).withTrace(state.attrs)
+
+	ctx = context.WithValue(ctx, handlerKey{}, node)
+
+	return withChannels(ctx, func(node *attrsNode) *attrsNode {
+		return node.withTrace(state.attrs)
+	})
+}
+
+// WithTrace stores tc in ctx as the remote parent of operations started with ctx.
+func WithTrace(ctx context.Context, tc TraceContext) context.Context {
+	if !tc.IsValid() {
+		return ctx
+	}
+
+	return context.WithValue(ctx, traceKey{}, traceState{tc: tc})
+}
+
+// TraceFromContext returns the trace context of the current operation or
+// the remote parent stored by WithTrace.
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	state, ok := ctx.Value(traceKey{}).(traceState)
+
+	return state.tc, ok
+}
+
+// InjectTraceparent sets the traceparent header of carrier to the trace context of ctx.
+func InjectTraceparent(ctx context.Context, carrier Carrier) {
+	tc, ok := TraceFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	carrier.Set(TraceparentHeader, tc.String())
+}
+
+// ExtractTraceparent stores the carrier traceparent in ctx by WithTrace,
+// ctx is returned unchanged if the header is missing or invalid.
+func ExtractTraceparent(ctx context.Context, carrier Carrier) context.Context {
+	tc, err := ParseTraceparent(carrier.Get(TraceparentHeader))
+	if err != nil {
+		return ctx
+	}
+
+	return WithTrace(ctx, tc)
+}
+
+var newTraceID = randomTraceID
+
+func randomTraceID() (traceID [16]byte) {
+	for traceID == [16]byte{} {
+		_, _ = rand.Read(traceID[:])
+	}
+
+	return traceID
+}
+
+var newSpanID = randomSpanID
+
+func randomSpanID() (spanID [8]byte) {
+	for spanID == [8]byte{} {
+		_, _ = rand.Read(spanID[:])
+	}
+
+	return spanID
+}
+
+// startTrace returns the operation trace state, the trace is continued if
+// ctx has one, otherwise a new trace is started if newTrace is true.
+func startTrace(ctx context.Context, newTrace bool) (traceState, bool) {
+	parent, ok := ctx.Value(traceKey{}).(traceState)
+
+	switch {
+	case ok:
+		return localTraceState(
+			TraceContext{
+				TraceID: parent.tc.TraceID,
+				SpanID:  newSpanID(),
+				Flags:   parent.tc.Flags,
+			},
+		), true
+	case newTrace:
+		return localTraceState(
+			TraceContext{
+				TraceID: newTraceID(),
+				SpanID:  newSpanID(),
+				Flags:   traceFlagSampled,
+			},
+		), true
+	default:
+		return traceState{}, false
+	}
+}
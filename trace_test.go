@@ -0,0 +1,217 @@
+package alog_test
+
+import (
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/amidgo/alog"
+	"github.com/amidgo/alog/alogtest"
+)
+
+func sequentialTraceIDs(t *testing.T) {
+	traceID, spanID := byte(0), byte(0)
+
+	restore := alog.SetNewTraceIDs(
+		func() [16]byte {
+			traceID++
+
+			return [16]byte{15: traceID}
+		},
+		func() [8]byte {
+			spanID++
+
+			return [8]byte{7: spanID}
+		},
+	)
+
+	t.Cleanup(restore)
+}
+
+func Test_ParseTraceparent(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tc, err := alog.ParseTraceparent(traceparent)
+	if err != nil {
+		t.Fatalf("parse traceparent: %s", err)
+	}
+
+	if tc.TraceIDString() != "4bf92f3577b34da6a3ce929d0e0e4736" || tc.SpanIDString() != "00f067aa0ba902b7" || tc.Flags != 1 {
+		t.Fatalf("unexpected trace context %+v", tc)
+	}
+
+	if tc.String() != traceparent {
+		t.Fatalf("unexpected traceparent %s", tc)
+	}
+
+	future, err := alog.ParseTraceparent("cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future")
+	if err != nil || future.TraceIDString() != tc.TraceIDString() || future.Flags != 0 {
+		t.Fatalf("parse future version: %+v, %v", future, err)
+	}
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736x00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-600f067aa0ba902b7-01",
+		"cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01x",
+	}
+
+	for _, traceparent := range invalid {
+		_, err := alog.ParseTraceparent(traceparent)
+		if err == nil {
+			t.Errorf("expected error for %q", traceparent)
+		}
+	}
+}
+
+func Test_Start_Trace(t *testing.T) {
+	sequentialTraceIDs(t)
+
+	const (
+		traceID    = "00000000000000000000000000000001"
+		rootSpanID = "0000000000000001"
+		childSpan  = "0000000000000002"
+	)
+
+	// the trace attributes precede the context attributes, the child
+	// operation replaces the span_id of its parent
+	root := alogtest.Scope{}.
+		With(alog.TraceIDKey, traceID, alog.SpanIDKey, rootSpanID).
+		Op("request", "user_id", 1)
+	child := alogtest.Scope{}.
+		With(alog.TraceIDKey, traceID, alog.SpanIDKey, childSpan).
+		Op("request", "user_id", 1).
+		Op("query")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		root.Start(),
+		child.Start(),
+		child.Info("rows", "count", 2),
+		child.Finish(),
+		root.Finish(),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+
+	rootOp := alog.StartWithOptions(ctx, "request", &alog.StartOptions{Trace: true}, "user_id", 1)
+
+	childOp := alog.Start(rootOp.Context(), "query")
+	childOp.Info("rows", "count", 2)
+
+	header := http.Header{}
+
+	alog.InjectTraceparent(childOp.Context(), alog.HeaderCarrier(header))
+
+	expectedTraceparent := "00-" + traceID + "-" + childSpan + "-01"
+	if header.Get(alog.TraceparentHeader) != expectedTraceparent {
+		t.Fatalf("unexpected traceparent %q", header.Get(alog.TraceparentHeader))
+	}
+
+	childOp.Finish()
+	rootOp.Finish()
+}
+
+func Test_Start_ExtractTraceparent(t *testing.T) {
+	sequentialTraceIDs(t)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	op := alogtest.Scope{}.
+		With(alog.TraceIDKey, traceID, alog.SpanIDKey, "0000000000000001").
+		Op("request")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.Info("remote parent"),
+		op.Start(),
+		op.Finish(),
+	)
+
+	header := http.Header{}
+	header.Set(alog.TraceparentHeader, "00-"+traceID+"-00f067aa0ba902b7-00")
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.ExtractTraceparent(ctx, alog.HeaderCarrier(header))
+
+	alog.Info(ctx, "remote parent")
+
+	aop := alog.StartWithOptions(ctx, "request", &alog.StartOptions{Trace: true})
+
+	carrier := alog.MapCarrier{}
+
+	alog.InjectTraceparent(aop.Context(), carrier)
+
+	if carrier[alog.TraceparentHeader] != "00-"+traceID+"-0000000000000001-00" {
+		t.Fatalf("unexpected traceparent %q", carrier[alog.TraceparentHeader])
+	}
+
+	aop.Finish()
+}
+
+func Test_Start_TraceWithGroup(t *testing.T) {
+	sequentialTraceIDs(t)
+
+	const (
+		traceID = "00000000000000000000000000000001"
+		spanID  = "0000000000000001"
+	)
+
+	scope := alogtest.Scope{}.With(alog.TraceIDKey, traceID, alog.SpanIDKey, spanID)
+	op := scope.With("request_id", "r1").WithGroup("req").Op("request")
+
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		op.Start(),
+		op.Info("rows", "count", 2),
+		scope.Log(slog.LevelInfo, "audit", "user_id", 1),
+		op.Finish(),
+	)
+
+	audit := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		scope.With("request_id", "r1").WithGroup("req").Op("request").Info("audit", "user_id", 1),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.ContextNamed(ctx, "audit", audit)
+	ctx = alog.With(ctx, "request_id", "r1")
+	ctx = alog.WithGroup(ctx, "req")
+
+	aop := alog.StartWithOptions(ctx, "request", &alog.StartOptions{Trace: true})
+	aop.Info("rows", "count", 2)
+
+	alog.Channel(aop.Context(), "audit").Info("audit", "user_id", 1)
+	alog.Info(alog.Context(aop.Context(), handler), "audit", "user_id", 1)
+
+	aop.Finish()
+}
+
+func Test_Start_WithoutTrace(t *testing.T) {
+	handler := alogtest.NewHandler(t,
+		(*alogtest.AssertOptions)(nil),
+		alogtest.NewOp("request").Start(),
+	)
+
+	ctx := alog.Context(t.Context(), handler)
+	ctx = alog.ExtractTraceparent(ctx, alog.MapCarrier{alog.TraceparentHeader: "invalid"})
+
+	op := alog.Start(ctx, "request")
+
+	if _, ok := alog.TraceFromContext(op.Context()); ok {
+		t.Fatal("unexpected trace in context")
+	}
+
+	carrier := alog.MapCarrier{}
+
+	alog.InjectTraceparent(op.Context(), carrier)
+
+	if len(carrier) != 0 {
+		t.Fatalf("unexpected carrier headers %v", carrier)
+	}
+}